@@ -1,15 +1,15 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"log"
 
 	"github.com/akhripko/gremlin-grammes/src/enrollment"
+	"github.com/akhripko/gremlin-grammes/src/gremlin"
 
 	"github.com/akhripko/gremlin-grammes/src/options"
 	"github.com/northwesternmutual/grammes"
-	p "github.com/northwesternmutual/grammes/query/predicate"
-	t "github.com/northwesternmutual/grammes/query/traversal"
 )
 
 func main() {
@@ -17,6 +17,21 @@ func main() {
 	//log.SetFlags(log.Lmsgprefix)
 	//log.SetPrefix(">>")
 	log.Println(config.GremlinAddr)
+	if err := enrollment.SetPageTokenSecret([]byte(config.PageTokenSecret)); err != nil {
+		log.Fatalf("Config error: APP_PAGE_TOKEN_SECRET: %s\n", err.Error())
+	}
+	if config.MaxPageSize <= 0 {
+		log.Fatalf("Config error: APP_MAX_PAGE_SIZE must be positive, got %d\n", config.MaxPageSize)
+	}
+	policy, err := enrollment.ParseUnknownTypePolicy(config.GremlinUnknownTypes)
+	if err != nil {
+		log.Fatalf("Config error: %s\n", err.Error())
+	}
+	enrollment.SetUnknownTypePolicy(policy)
+	version, err := enrollment.ParseGraphSONVersion(config.GremlinGraphSONVersion)
+	if err != nil {
+		log.Fatalf("Config error: %s\n", err.Error())
+	}
 
 	// Load CA cert
 	//caCert, err := ioutil.ReadFile("SFSRootCAG2.pem")
@@ -33,53 +48,31 @@ func main() {
 	//log.Println(tlsConfig)
 
 	// Creates a new client with the localhost IP.
-	client, err := grammes.DialWithWebSocket("wss://127.0.0.1:8182",
+	client, err := gremlin.Dial("wss://127.0.0.1:8182", config.GremlinSerializer,
 		grammes.WithTLS(&tls.Config{InsecureSkipVerify: true}))
 	if err != nil {
 		log.Fatalf("Error while creating client: %s\n", err.Error())
 	}
 
-	// Create a graph traversal.
-	g := grammes.Traversal()
-
-	query := g.V().Has("zip", "name", "78704").
-		In("lives").
-		HasLabel("provider").As("p").
-		OutE("provides").
-		And(t.NewTraversal().Has("max_rate", p.LessThanOrEqual(50)).Raw(),
-			t.NewTraversal().Has("min_rate", p.GreaterThanOrEqual(0)).Raw(),
-			t.NewTraversal().Has("service", "childCare").Raw()).
-		Limit(2).
-		Properties()
-
-	//query, err := enrollment.BuildQuery(&enrollment.GRPCModel{
-	//	PostalCode: "78704",
-	//	CareType:   "childCare",
-	//	HourlyRate: &enrollment.HourlyRateGRPCModel{Min: 0, Max: 50},
-	//	PageSize:   10,
-	//	PageToken:  "0",
-	//})
+	req := &enrollment.GRPCModel{
+		PostalCode: "78704",
+		CareType:   "childCare",
+		HourlyRate: &enrollment.HourlyRateGRPCModel{Min: 0, Max: 50},
+		PageSize:   10,
+	}
 
-	//query, err := enrollment.BuildQuery(&enrollment.GRPCModel{
+	//req := &enrollment.GRPCModel{
 	//	CareType:   "childCare",
 	//	HourlyRate: &enrollment.HourlyRateGRPCModel{Min: 0, Max: 50},
 	//	PageSize:   10,
-	//	PageToken: "2",
-	//})
-
-	log.Printf("\nquery:\n===========\n%s\n===========\n", query.String())
-
-	res, err := client.ExecuteQuery(query)
-	if err != nil {
-		log.Fatalf("Querying error: %s\n", err.Error())
-	}
+	//}
 
-	data, err := enrollment.UnmarshalStringList(append(res, res[0]))
+	searcher := enrollment.NewSearcher(client, config.MaxPageSize, enrollment.WithGraphSONVersion(version))
+	data, err := searcher.Search(context.Background(), req)
 	if err != nil {
-		log.Fatalf("Querying error: %s\n", err.Error())
+		log.Fatalf("Search error: %s\n", err.Error())
 	}
-	log.Printf("\ncount:%d\n%v\n", len(data), data)
-	log.Printf("\ncount:%d\n", len(data))
+	log.Printf("\ncount:%d of %d\n%v\nnext page:%s\n", len(data.SitterIDs), data.Total, data.SitterIDs, data.NextPageToken)
 }
 
 type GremlinInt32List struct {
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log"
+	"net"
+
+	"github.com/akhripko/gremlin-grammes/src/enrollment"
+	"github.com/akhripko/gremlin-grammes/src/gremlin"
+	"github.com/akhripko/gremlin-grammes/src/options"
+	"github.com/akhripko/gremlin-grammes/src/server"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	config := options.ReadEnv()
+	if err := enrollment.SetPageTokenSecret([]byte(config.PageTokenSecret)); err != nil {
+		log.Fatalf("Config error: APP_PAGE_TOKEN_SECRET: %s\n", err.Error())
+	}
+	if config.MaxPageSize <= 0 {
+		log.Fatalf("Config error: APP_MAX_PAGE_SIZE must be positive, got %d\n", config.MaxPageSize)
+	}
+	policy, err := enrollment.ParseUnknownTypePolicy(config.GremlinUnknownTypes)
+	if err != nil {
+		log.Fatalf("Config error: %s\n", err.Error())
+	}
+	enrollment.SetUnknownTypePolicy(policy)
+	version, err := enrollment.ParseGraphSONVersion(config.GremlinGraphSONVersion)
+	if err != nil {
+		log.Fatalf("Config error: %s\n", err.Error())
+	}
+
+	client, err := gremlin.Dial(config.GremlinAddr, config.GremlinSerializer)
+	if err != nil {
+		log.Fatalf("Error while creating client: %s\n", err.Error())
+	}
+
+	lis, err := net.Listen("tcp", config.GRPCAddr)
+	if err != nil {
+		log.Fatalf("Listen error: %s\n", err.Error())
+	}
+	srv := grpc.NewServer()
+	server.New(enrollment.NewSearcher(client, config.MaxPageSize, enrollment.WithGraphSONVersion(version))).Register(srv)
+
+	log.Printf("serving on %s, gremlin %s\n", lis.Addr(), config.GremlinAddr)
+	if err := srv.Serve(lis); err != nil {
+		log.Fatalf("Serve error: %s\n", err.Error())
+	}
+}
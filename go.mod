@@ -7,5 +7,7 @@ replace github.com/northwesternmutual/grammes v1.2.0 => github.com/akhripko/gram
 require (
 	github.com/northwesternmutual/grammes v1.2.0
 	github.com/spf13/viper v1.7.1
-	github.com/stretchr/testify v1.3.0
+	github.com/stretchr/testify v1.7.0
+	google.golang.org/grpc v1.43.0
+	google.golang.org/protobuf v1.27.1
 )
@@ -0,0 +1,209 @@
+package enrollment
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// AttributeError is returned by strict accessors and coercions when the attribute cannot be read as the target
+type AttributeError struct {
+	Type DBType
+	// Target is the go type the attribute is read as
+	Target string
+	Reason string
+}
+
+func (e *AttributeError) Error() string {
+	t := string(e.Type)
+	if len(t) == 0 {
+		t = nullValue
+	}
+	if len(e.Reason) == 0 {
+		return fmt.Sprintf("cannot read %s as %s", t, e.Target)
+	}
+	return fmt.Sprintf("cannot read %s as %s: %s", t, e.Target, e.Reason)
+}
+
+func attributeError(a Attribute, target, reason string) error {
+	return &AttributeError{Type: a.Type, Target: target, Reason: reason}
+}
+
+// AsString returns the value of g:String, other types are an error
+func (a Attribute) AsString() (string, error) {
+	if a.Type != TypeString {
+		return "", attributeError(a, "string", "")
+	}
+	return a.StringValue(), nil
+}
+
+// AsBool returns the value of g:Boolean, other types are an error
+func (a Attribute) AsBool() (bool, error) {
+	if a.Type != TypeBoolean {
+		return false, attributeError(a, "bool", "")
+	}
+	return a.BoolValue(), nil
+}
+
+// AsInt32 returns the value of g:Int32, other types are an error
+func (a Attribute) AsInt32() (int32, error) {
+	if a.Type != TypeInteger {
+		return 0, attributeError(a, "int32", "")
+	}
+	return a.Int32Value(), nil
+}
+
+// AsInt64 returns the value of g:Long (g:Int64), other types are an error
+func (a Attribute) AsInt64() (int64, error) {
+	if a.Type != TypeLong {
+		return 0, attributeError(a, "int64", "")
+	}
+	return a.Int64Value(), nil
+}
+
+// AsFloat64 returns the value of g:Double or g:Float, other types are an error
+func (a Attribute) AsFloat64() (float64, error) {
+	if a.Type != TypeDouble && a.Type != TypeFloat {
+		return 0, attributeError(a, "float64", "")
+	}
+	return a.Float64Value(), nil
+}
+
+// AsTime returns the value of g:Date or g:Timestamp, other types are an error
+func (a Attribute) AsTime() (time.Time, error) {
+	if a.Type != TypeDate && a.Type != TypeTimestamp {
+		return time.Time{}, attributeError(a, "time.Time", "")
+	}
+	return a.TimeValue(), nil
+}
+
+// AsUUID returns lowercase uuid string of g:UUID, other types are an error
+func (a Attribute) AsUUID() (string, error) {
+	if a.Type != TypeUUID {
+		return "", attributeError(a, "uuid", "")
+	}
+	return a.UUIDValue(), nil
+}
+
+// AsList returns the items of g:List, other types are an error
+func (a Attribute) AsList() (List, error) {
+	if a.Type != TypeList {
+		return nil, attributeError(a, "List", "")
+	}
+	return a.ListValue(), nil
+}
+
+// AsMap returns the value of g:Map, other types are an error
+func (a Attribute) AsMap() (Map, error) {
+	if a.Type != TypeMap {
+		return Map{}, attributeError(a, "Map", "")
+	}
+	return a.MapValue(), nil
+}
+
+// CoerceInt64 reads g:Int32, g:Long, gx:Byte, gx:Int16, gx:BigInteger and integer g:String,
+// floating point numbers are an error. Decode reads integers the same way.
+func (a Attribute) CoerceInt64() (int64, error) {
+	n, err := coerceInt64(a)
+	if err != nil {
+		return 0, coerceError(a, "int64", err)
+	}
+	return n, nil
+}
+
+// CoerceInt32 reads the same values as CoerceInt64, values out of int32 range are an error
+func (a Attribute) CoerceInt32() (int32, error) {
+	n, err := coerceInt64(a)
+	if err != nil {
+		return 0, coerceError(a, "int32", err)
+	}
+	if n < math.MinInt32 || n > math.MaxInt32 {
+		return 0, attributeError(a, "int32", "value out of range")
+	}
+	return int32(n), nil
+}
+
+// CoerceFloat64 reads g:Int32, g:Long, gx:Byte, gx:Int16, gx:BigInteger, g:Float, g:Double and numeric g:String.
+// Integers which cannot be represented in float64 exactly are an error. Decode reads numbers the same way.
+func (a Attribute) CoerceFloat64() (float64, error) {
+	f, err := coerceFloat64(a)
+	if err != nil {
+		return 0, coerceError(a, "float64", err)
+	}
+	return f, nil
+}
+
+func coerceError(a Attribute, target string, err error) error {
+	if err == errNotInteger || err == errNotNumber {
+		return attributeError(a, target, "")
+	}
+	return attributeError(a, target, err.Error())
+}
+
+// UnmarshalStringListStrict is UnmarshalStringList failing on items which are not g:String
+func UnmarshalStringListStrict(recs [][]byte) ([]string, error) {
+	var items []string
+	err := StreamRecords(recs, func(a Attribute) error {
+		v, err := a.AsString()
+		if err != nil {
+			return fmt.Errorf("item %d: %w", len(items), err)
+		}
+		items = append(items, v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UnmarshalInt32ListStrict is UnmarshalInt32List failing on items which are not g:Int32
+func UnmarshalInt32ListStrict(recs [][]byte) ([]int32, error) {
+	var items []int32
+	err := StreamRecords(recs, func(a Attribute) error {
+		v, err := a.AsInt32()
+		if err != nil {
+			return fmt.Errorf("item %d: %w", len(items), err)
+		}
+		items = append(items, v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UnmarshalInt64List joins g:List records of integers into one list, items are read by CoerceInt64
+func UnmarshalInt64List(recs [][]byte) ([]int64, error) {
+	var items []int64
+	err := StreamRecords(recs, func(a Attribute) error {
+		v, err := a.CoerceInt64()
+		if err != nil {
+			return fmt.Errorf("item %d: %w", len(items), err)
+		}
+		items = append(items, v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UnmarshalFloat64List joins g:List records of numbers into one list, items are read by CoerceFloat64
+func UnmarshalFloat64List(recs [][]byte) ([]float64, error) {
+	var items []float64
+	err := StreamRecords(recs, func(a Attribute) error {
+		v, err := a.CoerceFloat64()
+		if err != nil {
+			return fmt.Errorf("item %d: %w", len(items), err)
+		}
+		items = append(items, v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
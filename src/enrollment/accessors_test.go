@@ -0,0 +1,192 @@
+package enrollment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttribute_StrictAccessors(t *testing.T) {
+	s, err := Attribute{Type: TypeString, Value: "s1"}.AsString()
+	require.NoError(t, err)
+	assert.Equal(t, "s1", s)
+	_, err = Attribute{Type: TypeUUID, Value: "41d2e28a-20a4-4ab0-b379-d810dede3786"}.AsString()
+	assert.Error(t, err)
+
+	i, err := Attribute{Type: TypeInteger, Value: int32(3)}.AsInt32()
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), i)
+	_, err = Attribute{Type: TypeLong, Value: int64(3)}.AsInt32()
+	assert.EqualError(t, err, "cannot read g:Long as int32")
+
+	l, err := Attribute{Type: TypeLong, Value: int64(3)}.AsInt64()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), l)
+
+	f, err := Attribute{Type: TypeFloat, Value: 4.5}.AsFloat64()
+	require.NoError(t, err)
+	assert.Equal(t, 4.5, f)
+	_, err = Attribute{Type: TypeInteger, Value: int32(4)}.AsFloat64()
+	var attrErr *AttributeError
+	require.True(t, errors.As(err, &attrErr))
+	assert.Equal(t, TypeInteger, attrErr.Type)
+	assert.Equal(t, "float64", attrErr.Target)
+
+	b, err := Attribute{Type: TypeBoolean, Value: true}.AsBool()
+	require.NoError(t, err)
+	assert.True(t, b)
+	_, err = Attribute{}.AsBool()
+	assert.EqualError(t, err, "cannot read null as bool")
+
+	now := time.Unix(1600000000, 0).UTC()
+	tm, err := Attribute{Type: TypeDate, Value: now}.AsTime()
+	require.NoError(t, err)
+	assert.Equal(t, now, tm)
+
+	_, err = Attribute{Type: TypeSet, Value: List{}}.AsList()
+	assert.Error(t, err)
+	_, err = Attribute{Type: TypeList, Value: List{}}.AsMap()
+	assert.Error(t, err)
+}
+
+func TestAttribute_Coerce(t *testing.T) {
+	tests := []struct {
+		a   Attribute
+		i32 int32
+		i64 int64
+		f64 float64
+	}{
+		{a: Attribute{Type: TypeInteger, Value: int32(-3)}, i32: -3, i64: -3, f64: -3},
+		{a: Attribute{Type: TypeLong, Value: int64(7)}, i32: 7, i64: 7, f64: 7},
+		{a: Attribute{Type: TypeString, Value: " 12 "}, i32: 12, i64: 12, f64: 12},
+	}
+	for _, test := range tests {
+		i32, err := test.a.CoerceInt32()
+		require.NoError(t, err)
+		assert.Equal(t, test.i32, i32)
+		i64, err := test.a.CoerceInt64()
+		require.NoError(t, err)
+		assert.Equal(t, test.i64, i64)
+		f64, err := test.a.CoerceFloat64()
+		require.NoError(t, err)
+		assert.Equal(t, test.f64, f64)
+	}
+
+	f, err := Attribute{Type: TypeString, Value: "12.5"}.CoerceFloat64()
+	require.NoError(t, err)
+	assert.Equal(t, 12.5, f)
+	f, err = Attribute{Type: TypeFloat, Value: 4.5}.CoerceFloat64()
+	require.NoError(t, err)
+	assert.Equal(t, 4.5, f)
+
+	_, err = Attribute{Type: TypeDouble, Value: 4.5}.CoerceInt64()
+	assert.EqualError(t, err, "cannot read g:Double as int64")
+	_, err = Attribute{Type: TypeString, Value: "12.5"}.CoerceInt32()
+	assert.EqualError(t, err, `cannot read g:String as int32: "12.5" is not a number`)
+	_, err = Attribute{Type: TypeLong, Value: int64(math.MaxInt32 + 1)}.CoerceInt32()
+	assert.EqualError(t, err, "cannot read g:Long as int32: value out of range")
+	_, err = Attribute{Type: TypeString, Value: "99999999999999999999"}.CoerceInt64()
+	assert.EqualError(t, err, "cannot read g:String as int64: value out of range")
+	_, err = Attribute{Type: TypeLong, Value: int64(1<<53 + 1)}.CoerceFloat64()
+	assert.EqualError(t, err, "cannot read g:Long as float64: value loses precision")
+	_, err = Attribute{Type: TypeBoolean, Value: true}.CoerceFloat64()
+	assert.Error(t, err)
+
+	// only finite decimal strings are numbers
+	for _, v := range []string{"NaN", "Inf", "-inf", "infinity", "0x1p4", "0X10"} {
+		_, err = Attribute{Type: TypeString, Value: v}.CoerceFloat64()
+		assert.EqualError(t, err, fmt.Sprintf("cannot read g:String as float64: %q is not a number", v))
+	}
+	_, err = Attribute{Type: TypeString, Value: "1e400"}.CoerceFloat64()
+	assert.EqualError(t, err, "cannot read g:String as float64: value out of range")
+	f, err = Attribute{Type: TypeString, Value: "-1.5e3"}.CoerceFloat64()
+	require.NoError(t, err)
+	assert.Equal(t, -1500.0, f)
+}
+
+func TestUnmarshalListStrict(t *testing.T) {
+	recs := [][]byte{
+		[]byte(`{"@type":"g:List","@value":["s1","s2"]}`),
+		[]byte(`{"@type":"g:List","@value":[{"@type":"g:Int32","@value":3}]}`),
+	}
+	ids, err := UnmarshalStringList(recs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1", "s2", "3"}, ids)
+	_, err = UnmarshalStringListStrict(recs)
+	assert.EqualError(t, err, "item 2: cannot read g:Int32 as string")
+	ids, err = UnmarshalStringListStrict(recs[:1])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1", "s2"}, ids)
+
+	recs = [][]byte{
+		[]byte(`{"@type":"g:List","@value":[{"@type":"g:Int32","@value":3},{"@type":"g:Float","@value":4.5}]}`),
+	}
+	values, err := UnmarshalInt32List(recs)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{3, 0}, values)
+	_, err = UnmarshalInt32ListStrict(recs)
+	var attrErr *AttributeError
+	require.True(t, errors.As(err, &attrErr))
+	assert.Equal(t, TypeFloat, attrErr.Type)
+
+	floats, err := UnmarshalFloat64List(recs)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{3, 4.5}, floats)
+	_, err = UnmarshalInt64List(recs)
+	assert.EqualError(t, err, "item 1: cannot read g:Float as int64")
+
+	longs, err := UnmarshalInt64List([][]byte{[]byte(`{"@type":"g:List","@value":[{"@type":"g:Int32","@value":3},{"@type":"g:Int64","@value":4}]}`)})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, longs)
+}
+
+func TestAttribute_Coerce_SameAsDecode(t *testing.T) {
+	tests := []Attribute{
+		{Type: TypeInteger, Value: int32(-3)},
+		{Type: TypeLong, Value: int64(7)},
+		{Type: TypeByte, Value: int8(-8)},
+		{Type: TypeByte, Value: int32(8)},
+		{Type: TypeInt16, Value: int16(300)},
+		{Type: TypeInt16, Value: int64(-300)},
+		{Type: TypeBigInteger, Value: big.NewInt(42)},
+		{Type: TypeString, Value: " 12 "},
+		{Type: TypeFloat, Value: float32(2.5)},
+		{Type: TypeFloat, Value: 4.5},
+		{Type: TypeDouble, Value: 1.25},
+		{Type: TypeString, Value: "12.5"},
+		{Type: TypeString, Value: "NaN"},
+		{Type: TypeString, Value: "0x1p4"},
+		{Type: TypeLong, Value: int64(1<<53 + 1)},
+		{Type: TypeBigInteger, Value: new(big.Int).Lsh(big.NewInt(1), 70)},
+		{Type: TypeChar, Value: int32('A')},
+		{Type: TypeBoolean, Value: true},
+	}
+	for _, a := range tests {
+		i64, coerceErr := a.CoerceInt64()
+		var decoded int64
+		decodeErr := Decode(a, &decoded)
+		assert.Equal(t, coerceErr == nil, decodeErr == nil, "%s %v", a.Type, a.Value)
+		assert.Equal(t, i64, decoded, "%s %v", a.Type, a.Value)
+
+		f64, coerceErr := a.CoerceFloat64()
+		var decodedFloat float64
+		decodeErr = Decode(a, &decodedFloat)
+		assert.Equal(t, coerceErr == nil, decodeErr == nil, "%s %v", a.Type, a.Value)
+		assert.Equal(t, f64, decodedFloat, "%s %v", a.Type, a.Value)
+	}
+
+	f, err := Attribute{Type: TypeFloat, Value: float32(2.5)}.CoerceFloat64()
+	require.NoError(t, err)
+	assert.Equal(t, 2.5, f)
+	n, err := Attribute{Type: TypeByte, Value: int32(8)}.CoerceInt64()
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), n)
+	_, err = Attribute{Type: TypeBigInteger, Value: new(big.Int).Lsh(big.NewInt(1), 70)}.CoerceFloat64()
+	assert.EqualError(t, err, "cannot read gx:BigInteger as float64: value out of range")
+}
@@ -0,0 +1,101 @@
+package enrollment
+
+import (
+	"time"
+
+	t "github.com/northwesternmutual/grammes/query/traversal"
+)
+
+// MaxAvailabilityWindow is the longest time window a provider can be searched for
+const MaxAvailabilityWindow = 7 * 24 * time.Hour
+
+const minutesPerDay = 24 * 60
+
+// availability windows are searched within these years, so they fit unix milliseconds of the graph
+const (
+	minAvailabilityYear = 1970
+	maxAvailabilityYear = 9999
+)
+
+// TimeWindowGRPCModel is a time range [From, To) the provider has to be free for.
+// Weekday and time of day are taken in the location of From, the provider's local time is expected.
+//
+// Availability is stored in the graph as:
+//
+//	provider -available-> slot {weekday: 0 (Sunday) - 6, start_minute, end_minute: minutes since midnight}
+//	provider -unavailable-> exception {starts_at, ends_at: unix milliseconds}
+type TimeWindowGRPCModel struct {
+	From time.Time
+	To   time.Time
+}
+
+// daySegment is a part of the time window within one day
+type daySegment struct {
+	weekday     time.Weekday
+	startMinute int
+	endMinute   int
+}
+
+// addAvailabilityFilter keeps providers with a weekly slot covering every day of the window and without exceptions overlapping it
+// Windows of the same number of days give the same script, the days are bound by their index.
+func addAvailabilityFilter(g t.String, req *GRPCModel, b Bindings) t.String {
+	window := req.Availability
+	if window == nil || !window.To.After(window.From) {
+		return g
+	}
+	segments := splitByDays(window.From, window.To)
+	limits := make([]t.String, 0, len(segments)+1)
+	for i, s := range segments {
+		weekday := b.bindInt(indexedBinding(weekdayBinding, i), int(s.weekday))
+		startMinute := b.bindInt(indexedBinding(startMinuteBinding, i), s.startMinute)
+		endMinute := b.bindInt(indexedBinding(endMinuteBinding, i), s.endMinute)
+		limits = append(limits, t.NewTraversal().Out("available").
+			Has("weekday", weekday).
+			Has("start_minute", predicate("lte", startMinute.String())).
+			Has("end_minute", predicate("gte", endMinute.String())).Raw())
+	}
+	windowTo := b.bindLong(windowToBinding, unixMillis(window.To))
+	windowFrom := b.bindLong(windowFromBinding, unixMillis(window.From))
+	exceptions := t.NewTraversal().Out("unavailable").
+		Has("starts_at", predicate("lt", windowTo.String())).
+		Has("ends_at", predicate("gt", windowFrom.String()))
+	limits = append(limits, t.NewTraversal().Not(exceptions).Raw())
+	return g.And(limits...)
+}
+
+func splitByDays(from, to time.Time) []daySegment {
+	var segments []daySegment
+	for start := from; start.Before(to); {
+		y, m, d := start.Date()
+		nextDay := time.Date(y, m, d+1, 0, 0, 0, 0, start.Location())
+		s := daySegment{
+			weekday:     start.Weekday(),
+			startMinute: minuteOfDay(start),
+			endMinute:   minutesPerDay,
+		}
+		if to.Before(nextDay) {
+			s.endMinute = endMinuteOfDay(to)
+		}
+		segments = append(segments, s)
+		start = nextDay
+	}
+	return segments
+}
+
+func minuteOfDay(tm time.Time) int {
+	return tm.Hour()*60 + tm.Minute()
+}
+
+// endMinuteOfDay rounds the end of the window up to the next minute, so its seconds are covered by the slot
+func endMinuteOfDay(tm time.Time) int {
+	m := minuteOfDay(tm)
+	if tm.Second() > 0 || tm.Nanosecond() > 0 {
+		m++
+	}
+	return m
+}
+
+func inAvailabilityYears(tm time.Time) bool {
+	y := tm.Year()
+	return y >= minAvailabilityYear && y <= maxAvailabilityYear
+}
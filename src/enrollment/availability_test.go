@@ -0,0 +1,84 @@
+package enrollment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/northwesternmutual/grammes"
+	t "github.com/northwesternmutual/grammes/query/traversal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BuildQuery_availability(te *testing.T) {
+	// Friday 18:00 - Saturday 02:30
+	from := time.Date(2020, time.October, 9, 18, 0, 0, 0, time.UTC)
+	to := time.Date(2020, time.October, 10, 2, 30, 0, 0, time.UTC)
+
+	g := grammes.Traversal()
+	expected := g.V().
+		Has("service", t.Custom("careType")).
+		InE("provides").As("e").
+		OutV().
+		HasLabel("provider").
+		And(t.NewTraversal().Out("available").
+			Has("weekday", t.Custom("Integer.valueOf(weekday0)")).Has("start_minute", predicate("lte", "Integer.valueOf(startMinute0)")).Has("end_minute", predicate("gte", "Integer.valueOf(endMinute0)")).Raw(),
+			t.NewTraversal().Out("available").
+				Has("weekday", t.Custom("Integer.valueOf(weekday1)")).Has("start_minute", predicate("lte", "Integer.valueOf(startMinute1)")).Has("end_minute", predicate("gte", "Integer.valueOf(endMinute1)")).Raw(),
+			t.NewTraversal().Not(t.NewTraversal().Out("unavailable").
+				Has("starts_at", predicate("lt", "Long.valueOf(windowTo)")).
+				Has("ends_at", predicate("gt", "Long.valueOf(windowFrom)"))).Raw()).
+		Order().By("sitter_id").
+		Limit(21).
+		Properties().HasKey("sitter_id").Value()
+
+	query, _, err := BuildQuery(&GRPCModel{
+		CareType:     "childCare",
+		Availability: &TimeWindowGRPCModel{From: from, To: to},
+	})
+	assert.NoError(te, err)
+
+	assert.Equal(te, expected.String(), query.String())
+}
+
+func Test_splitByDays(te *testing.T) {
+	loc := time.FixedZone("CDT", -5*60*60)
+	segments := splitByDays(
+		time.Date(2020, time.October, 9, 9, 15, 0, 0, loc),
+		time.Date(2020, time.October, 9, 17, 45, 0, 0, loc))
+	assert.Equal(te, []daySegment{{weekday: time.Friday, startMinute: 555, endMinute: 1065}}, segments)
+
+	segments = splitByDays(
+		time.Date(2020, time.October, 9, 22, 0, 0, 0, loc),
+		time.Date(2020, time.October, 11, 0, 0, 0, 0, loc))
+	assert.Equal(te, []daySegment{
+		{weekday: time.Friday, startMinute: 1320, endMinute: 1440},
+		{weekday: time.Saturday, startMinute: 0, endMinute: 1440},
+	}, segments)
+
+	// seconds of the end are covered by the next minute
+	segments = splitByDays(
+		time.Date(2020, time.October, 9, 9, 15, 30, 0, loc),
+		time.Date(2020, time.October, 9, 17, 45, 0, 1, loc))
+	assert.Equal(te, []daySegment{{weekday: time.Friday, startMinute: 555, endMinute: 1066}}, segments)
+
+	segments = splitByDays(
+		time.Date(2020, time.October, 9, 23, 0, 0, 0, loc),
+		time.Date(2020, time.October, 9, 23, 59, 59, 0, loc))
+	assert.Equal(te, []daySegment{{weekday: time.Friday, startMinute: 1380, endMinute: 1440}}, segments)
+}
+
+func TestValidate_Availability(te *testing.T) {
+	from := time.Date(2020, time.October, 9, 18, 0, 0, 0, time.UTC)
+	err := Validate(&GRPCModel{Availability: &TimeWindowGRPCModel{From: from, To: from}}, DefaultMaxPageSize)
+	assert.Equal(te, ValidationErrors{{Field: "Availability", Message: "To must be after From"}}, err)
+
+	err = Validate(&GRPCModel{Availability: &TimeWindowGRPCModel{From: from, To: from.AddDate(0, 0, 8)}}, DefaultMaxPageSize)
+	assert.Equal(te, ValidationErrors{{Field: "Availability", Message: "window must not be longer than 168h0m0s"}}, err)
+
+	far := time.Date(292277026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	err = Validate(&GRPCModel{Availability: &TimeWindowGRPCModel{From: far, To: far.Add(time.Hour)}}, DefaultMaxPageSize)
+	assert.Equal(te, ValidationErrors{{Field: "Availability", Message: "window must be within years 1970-9999"}}, err)
+
+	err = Validate(&GRPCModel{Availability: &TimeWindowGRPCModel{From: time.Time{}, To: from}}, DefaultMaxPageSize)
+	assert.Equal(te, ValidationErrors{{Field: "Availability", Message: "window must be within years 1970-9999"}}, err)
+}
@@ -0,0 +1,74 @@
+package enrollment
+
+import (
+	"strconv"
+
+	t "github.com/northwesternmutual/grammes/query/traversal"
+)
+
+// names of the query script variables
+const (
+	postalCodeBinding    = "postalCode"
+	genderBinding        = "gender"
+	careTypeBinding      = "careType"
+	lastSitterIDBinding  = "lastSitterID"
+	lastKeyBinding       = "lastKey"
+	maxDistanceBinding   = "maxDistance"
+	minYearsOfExpBinding = "minYearsOfExp"
+	minAvgRankBinding    = "minAvgRank"
+	activeSinceBinding   = "activeSince"
+	minRateBinding       = "minRate"
+	maxRateBinding       = "maxRate"
+	windowFromBinding    = "windowFrom"
+	windowToBinding      = "windowTo"
+	weekdayBinding       = "weekday"
+	startMinuteBinding   = "startMinute"
+	endMinuteBinding     = "endMinute"
+)
+
+// Bindings are values of the query script variables.
+// Request values are never put into the script text, so they can not change the script,
+// and the server compiles one script per query shape: the set of request filters,
+// the number of days of the availability window, the sort and the page size.
+// Execute query with its bindings: client.ExecuteBoundQuery(query, bindings, nil)
+type Bindings map[string]string
+
+// bind sets variable value and returns the variable to be used in the query
+func (b Bindings) bind(name string, value string) t.Custom {
+	b[name] = value
+	return t.Custom(name)
+}
+
+// numberParsers read numbers of the type from bound strings in the script
+var numberParsers = map[DBType]string{
+	TypeInteger: "Integer.valueOf",
+	TypeLong:    "Long.valueOf",
+	TypeFloat:   "Float.valueOf",
+	TypeDouble:  "Double.valueOf",
+}
+
+// bindNumber binds the decimal string form of a number of the type, TypeInteger, TypeLong, TypeFloat or TypeDouble,
+// and returns the script reading the number from the variable: bindings are sent as strings
+func (b Bindings) bindNumber(name string, typ DBType, value string) t.Custom {
+	b[name] = value
+	return t.Custom(numberParsers[typ] + "(" + name + ")")
+}
+
+func (b Bindings) bindInt(name string, v int) t.Custom {
+	return b.bindNumber(name, TypeInteger, strconv.Itoa(v))
+}
+
+func (b Bindings) bindLong(name string, v int64) t.Custom {
+	return b.bindNumber(name, TypeLong, strconv.FormatInt(v, 10))
+}
+
+// bindFloat32 binds a request number as double with the shortest decimal form of the float,
+// so 12.1 is compared as 12.1d and not as the float nearest to it
+func (b Bindings) bindFloat32(name string, v float32) t.Custom {
+	return b.bindNumber(name, TypeDouble, strconv.FormatFloat(float64(v), 'g', -1, 32))
+}
+
+// indexedBinding is the name of a variable bound once per item of a list
+func indexedBinding(name string, i int) string {
+	return name + strconv.Itoa(i)
+}
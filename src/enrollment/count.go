@@ -0,0 +1,43 @@
+package enrollment
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/northwesternmutual/grammes"
+	t "github.com/northwesternmutual/grammes/query/traversal"
+)
+
+// BuildCountQuery builds query counting all results of BuildQuery for the same request.
+// Sort, pagination and projection of the request are ignored.
+func BuildCountQuery(req *GRPCModel) (t.String, Bindings, error) {
+	var err error
+	g := grammes.Traversal()
+	if req == nil {
+		return g, nil, errors.New("empty request")
+	}
+	b := make(Bindings)
+	g, err = providers(g, req, b)
+	if err != nil {
+		return g, nil, err
+	}
+	return g.Count(), b, nil
+}
+
+// BuildCountResponse reads result of the query built by BuildCountQuery
+func BuildCountResponse(recs [][]byte) (int64, error) {
+	rows, err := UnmarshalList(recs)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	switch rows[0].Type {
+	case TypeLong:
+		return rows[0].Int64Value(), nil
+	case TypeInteger:
+		return int64(rows[0].Int32Value()), nil
+	}
+	return 0, fmt.Errorf("got %s where %s is expected", rows[0].Type, TypeLong)
+}
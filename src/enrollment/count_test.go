@@ -0,0 +1,59 @@
+package enrollment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/northwesternmutual/grammes"
+	t "github.com/northwesternmutual/grammes/query/traversal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BuildCountQuery(te *testing.T) {
+	g := grammes.Traversal()
+	expected := g.V().
+		Has("service", t.Custom("careType")).
+		InE("provides").As("e").
+		And(t.NewTraversal().Has("max_rate", predicate("lte", "Double.valueOf(maxRate)")).Raw()).
+		OutV().
+		HasLabel("provider").
+		Count()
+
+	query, _, err := BuildCountQuery(&GRPCModel{
+		CareType:   "childCare",
+		HourlyRate: &HourlyRateGRPCModel{Min: 0, Max: 100},
+		PageSize:   10,
+	})
+	assert.NoError(te, err)
+
+	assert.Equal(te, expected.String(), query.String())
+}
+
+func Test_BuildCountQuery_sameFilters(te *testing.T) {
+	req := &GRPCModel{
+		PostalCode:  "78704",
+		MaxDistance: 3,
+		Gender:      "female",
+		CareType:    "petCare",
+		HourlyRate:  &HourlyRateGRPCModel{Min: 10, Max: 30},
+		Sort:        &SortGRPCModel{Field: SortByYearsOfExp},
+	}
+	page, _, err := BuildQuery(req)
+	require.NoError(te, err)
+	count, _, err := BuildCountQuery(req)
+	require.NoError(te, err)
+
+	filters := strings.TrimSuffix(count.String(), ".count()")
+	assert.True(te, strings.HasPrefix(page.String(), filters+".order()"), page.String())
+}
+
+func Test_BuildCountResponse(te *testing.T) {
+	total, err := BuildCountResponse([][]byte{[]byte(`{"@type":"g:List","@value":[{"@type":"g:Int64","@value":42}]}`)})
+	require.NoError(te, err)
+	assert.Equal(te, int64(42), total)
+
+	total, err = BuildCountResponse([][]byte{[]byte(`{"@type":"g:List","@value":[]}`)})
+	require.NoError(te, err)
+	assert.Equal(te, int64(0), total)
+}
@@ -0,0 +1,108 @@
+package enrollment
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number Unscaled×10^-Scale, it is gx:BigDecimal as java.math.BigDecimal keeps it.
+// Zero value is 0.
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int32
+}
+
+// NewDecimal returns unscaled×10^-scale
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return Decimal{Unscaled: big.NewInt(unscaled), Scale: scale}
+}
+
+// ParseDecimal reads a decimal number: an optional sign, digits with an optional point and an optional exponent.
+// The scale is kept, so 12.50 is read as 1250 with scale 2.
+func ParseDecimal(s string) (Decimal, error) {
+	mantissa, exp := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("wrong decimal: %s", s)
+		}
+		mantissa, exp = s[:i], e
+	}
+	var scale int64
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		scale = int64(len(mantissa) - i - 1)
+		mantissa = mantissa[:i] + mantissa[i+1:]
+	}
+	digits := strings.TrimPrefix(strings.TrimPrefix(mantissa, "-"), "+")
+	if len(digits) == 0 || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("wrong decimal: %s", s)
+	}
+	n, ok := new(big.Int).SetString(mantissa, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("wrong decimal: %s", s)
+	}
+	scale -= exp
+	if scale < math.MinInt32 || scale > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("decimal scale out of range: %s", s)
+	}
+	return Decimal{Unscaled: n, Scale: int32(scale)}, nil
+}
+
+func (d Decimal) unscaled() *big.Int {
+	if d.Unscaled == nil {
+		return new(big.Int)
+	}
+	return d.Unscaled
+}
+
+// String writes the number without exponent, e.g. 12.50 for 1250 with scale 2
+func (d Decimal) String() string {
+	n := d.unscaled()
+	digits := new(big.Int).Abs(n).String()
+	var sign string
+	if n.Sign() < 0 {
+		sign = "-"
+	}
+	if d.Scale <= 0 {
+		if n.Sign() == 0 {
+			return "0"
+		}
+		return sign + digits + strings.Repeat("0", int(-d.Scale))
+	}
+	scale := int(d.Scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	point := len(digits) - scale
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Rat returns the number as an exact fraction
+func (d Decimal) Rat() *big.Rat {
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs32(d.Scale))), nil)
+	if d.Scale < 0 {
+		return new(big.Rat).SetInt(new(big.Int).Mul(d.unscaled(), pow))
+	}
+	return new(big.Rat).SetFrac(d.unscaled(), pow)
+}
+
+// Float64 returns the nearest float64 value
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// Cmp compares the numbers regardless of their scales: -1 if d < o, 0 if d == o, +1 if d > o
+func (d Decimal) Cmp(o Decimal) int {
+	return d.Rat().Cmp(o.Rat())
+}
+
+func abs32(v int32) int64 {
+	if v < 0 {
+		return -int64(v)
+	}
+	return int64(v)
+}
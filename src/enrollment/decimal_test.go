@@ -0,0 +1,49 @@
+package enrollment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		s        string
+		unscaled string
+		scale    int32
+		str      string
+	}{
+		{"12.50", "1250", 2, "12.50"},
+		{"-0.001", "-1", 3, "-0.001"},
+		{"+7", "7", 0, "7"},
+		{"1.5e3", "15", -2, "1500"},
+		{"1.5E-3", "15", 4, "0.0015"},
+		{"123456789012345678901234567890.123456789", "123456789012345678901234567890123456789", 9, "123456789012345678901234567890.123456789"},
+		{"0", "0", 0, "0"},
+		{".5", "5", 1, "0.5"},
+	}
+	for _, test := range tests {
+		d, err := ParseDecimal(test.s)
+		require.NoError(t, err, test.s)
+		assert.Equal(t, test.unscaled, d.Unscaled.String(), test.s)
+		assert.Equal(t, test.scale, d.Scale, test.s)
+		assert.Equal(t, test.str, d.String(), test.s)
+	}
+
+	for _, s := range []string{"", ".", "-", "1.2.3", "1e", "abc", "1_000", "--1", "0x10"} {
+		_, err := ParseDecimal(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestDecimal_Cmp(t *testing.T) {
+	a, err := ParseDecimal("12.50")
+	require.NoError(t, err)
+	assert.Equal(t, 0, a.Cmp(NewDecimal(125, 1)))
+	assert.Equal(t, -1, a.Cmp(NewDecimal(1251, 2)))
+	assert.Equal(t, 1, a.Cmp(Decimal{}))
+	assert.Equal(t, 12.5, a.Float64())
+	assert.Equal(t, "0", Decimal{}.String())
+	assert.Equal(t, "-1200", NewDecimal(-12, -2).String())
+}
@@ -0,0 +1,372 @@
+package enrollment
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+const tagName = "gremlin"
+
+// DecodeError tells which value of the result could not be decoded and why
+type DecodeError struct {
+	// Path is the location of the value, e.g. [0].years_of_exp
+	Path   string
+	Type   DBType
+	Target reflect.Type
+	Reason string
+}
+
+func (e *DecodeError) Error() string {
+	path := e.Path
+	if len(path) == 0 {
+		path = "result"
+	}
+	return fmt.Sprintf("cannot decode %s into %s at %s: %s", e.Type, e.Target, path, e.Reason)
+}
+
+var (
+	attributeType = reflect.TypeOf(Attribute{})
+	timeType      = reflect.TypeOf(time.Time{})
+	decimalType   = reflect.TypeOf(Decimal{})
+	bigIntType    = reflect.TypeOf(big.Int{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+)
+
+// Decode fills v with the value of a the way encoding/json does it for json.
+// Struct fields are matched by `gremlin:"name"` tag or by field name, `gremlin:"-"` skips the field.
+// A struct is read from valueMap, elementMap or project map, or from a vertex or an edge.
+// Folded and valueMap lists of one value are read into scalar fields, lists of several values need a slice field.
+// Numbers are widened to bigger types, narrowing is an error when the value does not fit.
+func Decode(a Attribute, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got %T", v)
+	}
+	return decodeValue("", a, rv.Elem())
+}
+
+// UnmarshalInto joins g:List records and decodes every item into an element of the slice v points to
+func UnmarshalInto(recs [][]byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("decode target must be a non-nil pointer to a slice, got %T", v)
+	}
+	items, err := UnmarshalList(recs)
+	if err != nil {
+		return err
+	}
+	return decodeSlice("", items, rv.Elem())
+}
+
+func decodeValue(path string, a Attribute, rv reflect.Value) error {
+	switch rv.Type() {
+	case attributeType:
+		rv.Set(reflect.ValueOf(a))
+		return nil
+	case timeType:
+		a, err := singleValue(path, a, rv)
+		if err != nil || a.Type == "" {
+			return err
+		}
+		tm, ok := a.Value.(time.Time)
+		if !ok {
+			return decodeError(path, a, rv, "not a date")
+		}
+		rv.Set(reflect.ValueOf(tm))
+		return nil
+	case durationType:
+		a, err := singleValue(path, a, rv)
+		if err != nil || a.Type == "" {
+			return err
+		}
+		if d, ok := a.Value.(time.Duration); ok {
+			rv.SetInt(int64(d))
+			return nil
+		}
+		n, err := coerceInt64(a)
+		if err != nil {
+			return decodeError(path, a, rv, err.Error())
+		}
+		rv.SetInt(n)
+		return nil
+	case decimalType:
+		a, err := singleValue(path, a, rv)
+		if err != nil || a.Type == "" {
+			return err
+		}
+		d, ok := decimalValue(a)
+		if !ok {
+			return decodeError(path, a, rv, "not a decimal number")
+		}
+		rv.Set(reflect.ValueOf(d))
+		return nil
+	case bigIntType:
+		a, err := singleValue(path, a, rv)
+		if err != nil || a.Type == "" {
+			return err
+		}
+		n, ok := bigIntValue(a)
+		if !ok {
+			return decodeError(path, a, rv, "not an integer")
+		}
+		if !rv.CanAddr() {
+			return decodeError(path, a, rv, "use *big.Int")
+		}
+		rv.Addr().Interface().(*big.Int).Set(n)
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if a.Type == "" {
+			return nil
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return decodeValue(path, a, rv.Elem())
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			return decodeError(path, a, rv, "unsupported interface")
+		}
+		if a.Value != nil {
+			rv.Set(reflect.ValueOf(a.Value))
+		}
+		return nil
+	case reflect.Struct:
+		return decodeStruct(path, a, rv)
+	case reflect.Map:
+		return decodeMap(path, a, rv)
+	case reflect.Slice:
+		items, ok := listItems(a)
+		if !ok {
+			if a.Type == "" {
+				return nil
+			}
+			items = List{a}
+		}
+		return decodeSlice(path, items, rv)
+	}
+	a, err := singleValue(path, a, rv)
+	if err != nil || a.Type == "" {
+		return err
+	}
+	return decodeScalar(path, a, rv)
+}
+
+// singleValue unwraps a list of one value and a property, so its value can be read into a scalar
+func singleValue(path string, a Attribute, rv reflect.Value) (Attribute, error) {
+	if items, ok := listItems(a); ok {
+		switch len(items) {
+		case 0:
+			return Attribute{}, nil
+		case 1:
+			a = items[0]
+		default:
+			return Attribute{}, decodeError(path, a, rv, fmt.Sprintf("got %d values where one is expected", len(items)))
+		}
+	}
+	switch a.Type {
+	case TypeVertexProperty:
+		return a.VertexPropertyValue().Value, nil
+	case TypeProperty:
+		return a.PropertyValue().Value, nil
+	}
+	return a, nil
+}
+
+func listItems(a Attribute) (List, bool) {
+	switch a.Type {
+	case TypeList, TypeSet:
+		return a.ListValue(), true
+	case TypeBulkSet:
+		return a.BulkSetValue().List(), true
+	}
+	return nil, false
+}
+
+func decodeSlice(path string, items List, rv reflect.Value) error {
+	s := reflect.MakeSlice(rv.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := decodeValue(path+"["+strconv.Itoa(i)+"]", item, s.Index(i)); err != nil {
+			return err
+		}
+	}
+	rv.Set(s)
+	return nil
+}
+
+func decodeMap(path string, a Attribute, rv reflect.Value) error {
+	if a.Type == "" {
+		return nil
+	}
+	if a.Type != TypeMap {
+		return decodeError(path, a, rv, "not a map")
+	}
+	if rv.Type().Key().Kind() != reflect.String {
+		return decodeError(path, a, rv, "map key must be a string")
+	}
+	entries := a.MapValue().Entries()
+	m := reflect.MakeMapWithSize(rv.Type(), len(entries))
+	for _, e := range entries {
+		key := e.Key.ToString()
+		value := reflect.New(rv.Type().Elem()).Elem()
+		if err := decodeValue(fieldPath(path, key), e.Value, value); err != nil {
+			return err
+		}
+		m.SetMapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()), value)
+	}
+	rv.Set(m)
+	return nil
+}
+
+func decodeStruct(path string, a Attribute, rv reflect.Value) error {
+	lookup, err := structSource(path, a, rv)
+	if err != nil || lookup == nil {
+		return err
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		name, ok := fieldName(rt.Field(i))
+		if !ok {
+			continue
+		}
+		value, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := decodeValue(fieldPath(path, name), value, rv.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldName returns name of the exported struct field from `gremlin` tag or the field name itself
+func fieldName(f reflect.StructField) (string, bool) {
+	if len(f.PkgPath) > 0 {
+		return "", false
+	}
+	tag, ok := f.Tag.Lookup(tagName)
+	switch {
+	case !ok || len(tag) == 0:
+		return f.Name, true
+	case tag == "-":
+		return "", false
+	}
+	return tag, true
+}
+
+// structSource returns a lookup of struct field values by name
+func structSource(path string, a Attribute, rv reflect.Value) (func(name string) (Attribute, bool), error) {
+	a, err := singleValue(path, a, rv)
+	if err != nil {
+		return nil, err
+	}
+	switch a.Type {
+	case "":
+		return nil, nil
+	case TypeMap:
+		return a.MapValue().Lookup, nil
+	case TypeVertex:
+		v := a.VertexValue()
+		return func(name string) (Attribute, bool) {
+			if props, ok := v.Properties[name]; ok {
+				items := make(List, 0, len(props))
+				for _, p := range props {
+					items = append(items, Attribute{Type: TypeVertexProperty, Value: p})
+				}
+				return Attribute{Type: TypeList, Value: items}, true
+			}
+			return elementToken(name, v.ID, v.Label)
+		}, nil
+	case TypeEdge:
+		e := a.EdgeValue()
+		return func(name string) (Attribute, bool) {
+			if p, ok := e.Properties[name]; ok {
+				return p.Value, true
+			}
+			return elementToken(name, e.ID, e.Label)
+		}, nil
+	}
+	return nil, decodeError(path, a, rv, "not a map, vertex or edge")
+}
+
+func elementToken(name string, id Attribute, label string) (Attribute, bool) {
+	switch T(name) {
+	case TID:
+		return id, true
+	case TLabel:
+		return Attribute{Type: TypeString, Value: label}, true
+	}
+	return Attribute{}, false
+}
+
+func decodeScalar(path string, a Attribute, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.String:
+		if a.Type == TypeChar {
+			rv.SetString(string(a.CharValue()))
+			return nil
+		}
+		switch v := a.Value.(type) {
+		case string:
+			rv.SetString(v)
+		case T:
+			rv.SetString(string(v))
+		case Direction:
+			rv.SetString(string(v))
+		default:
+			return decodeError(path, a, rv, "not a string")
+		}
+	case reflect.Bool:
+		v, ok := a.Value.(bool)
+		if !ok {
+			return decodeError(path, a, rv, "not a bool")
+		}
+		rv.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := coerceInt64(a)
+		if err != nil {
+			return decodeError(path, a, rv, err.Error())
+		}
+		if rv.OverflowInt(n) {
+			return decodeError(path, a, rv, "value overflows")
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := coerceInt64(a)
+		if err != nil {
+			return decodeError(path, a, rv, err.Error())
+		}
+		if n < 0 || rv.OverflowUint(uint64(n)) {
+			return decodeError(path, a, rv, "value overflows")
+		}
+		rv.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		f, err := coerceFloat64(a)
+		if err != nil {
+			return decodeError(path, a, rv, err.Error())
+		}
+		if rv.OverflowFloat(f) {
+			return decodeError(path, a, rv, "value overflows")
+		}
+		rv.SetFloat(f)
+	default:
+		return decodeError(path, a, rv, "unsupported target type")
+	}
+	return nil
+}
+
+func fieldPath(path, name string) string {
+	if len(path) == 0 {
+		return name
+	}
+	return path + "." + name
+}
+
+func decodeError(path string, a Attribute, rv reflect.Value, reason string) error {
+	return &DecodeError{Path: path, Type: a.Type, Target: rv.Type(), Reason: reason}
+}
@@ -0,0 +1,130 @@
+package enrollment
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testProvider struct {
+	ID         int64     `gremlin:"id"`
+	Label      string    `gremlin:"label"`
+	SitterID   string    `gremlin:"sitter_id"`
+	YearsOfExp int64     `gremlin:"years_of_exp"`
+	AvgRank    float64   `gremlin:"avg_rank"`
+	Services   []string  `gremlin:"service"`
+	LastActive time.Time `gremlin:"last_active_at"`
+	Nick       *string   `gremlin:"nick"`
+	Ignored    string    `gremlin:"-"`
+}
+
+func decodeJSON(t *testing.T, js string, v interface{}) error {
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(js), &a))
+	return Decode(a, v)
+}
+
+func TestDecode_ValueMap(t *testing.T) {
+	js := `{"@type":"g:Map","@value":[` +
+		`"sitter_id",{"@type":"g:List","@value":["s1"]},` +
+		`"years_of_exp",{"@type":"g:List","@value":[{"@type":"g:Int32","@value":5}]},` +
+		`"avg_rank",{"@type":"g:List","@value":[{"@type":"g:Float","@value":4.5}]},` +
+		`"service",{"@type":"g:List","@value":["childCare","petCare"]},` +
+		`"last_active_at",{"@type":"g:List","@value":[{"@type":"g:Date","@value":1600000000000}]},` +
+		`"Ignored",{"@type":"g:List","@value":["x"]}]}`
+	var p testProvider
+	err := decodeJSON(t, js, &p)
+	require.NoError(t, err)
+	assert.Equal(t, "s1", p.SitterID)
+	assert.Equal(t, int64(5), p.YearsOfExp)
+	assert.Equal(t, 4.5, p.AvgRank)
+	assert.Equal(t, []string{"childCare", "petCare"}, p.Services)
+	assert.Equal(t, time.Unix(1600000000, 0).UTC(), p.LastActive)
+	assert.Nil(t, p.Nick)
+	assert.Empty(t, p.Ignored)
+}
+
+func TestDecode_ElementMap(t *testing.T) {
+	js := `{"@type":"g:Map","@value":[` +
+		`{"@type":"g:T","@value":"id"},{"@type":"g:Int64","@value":1},` +
+		`{"@type":"g:T","@value":"label"},"provider",` +
+		`"sitter_id","s1","nick","ann","service","childCare"]}`
+	var p testProvider
+	err := decodeJSON(t, js, &p)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), p.ID)
+	assert.Equal(t, "provider", p.Label)
+	assert.Equal(t, "s1", p.SitterID)
+	require.NotNil(t, p.Nick)
+	assert.Equal(t, "ann", *p.Nick)
+	assert.Equal(t, []string{"childCare"}, p.Services)
+}
+
+func TestDecode_Vertex(t *testing.T) {
+	js := `{"@type":"g:Vertex","@value":{"id":{"@type":"g:Int64","@value":1},"label":"provider","properties":{` +
+		`"sitter_id":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":10},"value":"s1","label":"sitter_id"}}],` +
+		`"service":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":11},"value":"childCare","label":"service"}},` +
+		`{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":12},"value":"petCare","label":"service"}}]}}}`
+	var p testProvider
+	err := decodeJSON(t, js, &p)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), p.ID)
+	assert.Equal(t, "provider", p.Label)
+	assert.Equal(t, "s1", p.SitterID)
+	assert.Equal(t, []string{"childCare", "petCare"}, p.Services)
+}
+
+func TestUnmarshalInto_Project(t *testing.T) {
+	recs := [][]byte{[]byte(`{"@type":"g:List","@value":[` +
+		`{"@type":"g:Map","@value":["sitter_id","s1","years_of_exp",{"@type":"g:Int32","@value":3}]},` +
+		`{"@type":"g:Map","@value":["sitter_id","s2","years_of_exp",{"@type":"g:Int64","@value":7}]}]}`)}
+	var providers []testProvider
+	err := UnmarshalInto(recs, &providers)
+	require.NoError(t, err)
+	require.Len(t, providers, 2)
+	assert.Equal(t, "s1", providers[0].SitterID)
+	assert.Equal(t, int64(3), providers[0].YearsOfExp)
+	assert.Equal(t, "s2", providers[1].SitterID)
+	assert.Equal(t, int64(7), providers[1].YearsOfExp)
+}
+
+func TestDecode_MapAndScalars(t *testing.T) {
+	js := `{"@type":"g:Map","@value":["childCare",{"@type":"g:Int64","@value":3},"petCare",{"@type":"g:Int64","@value":1}]}`
+	var counts map[string]int
+	err := decodeJSON(t, js, &counts)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"childCare": 3, "petCare": 1}, counts)
+
+	var f float32
+	err = decodeJSON(t, `{"@type":"g:Int32","@value":7}`, &f)
+	require.NoError(t, err)
+	assert.Equal(t, float32(7), f)
+}
+
+func TestDecode_Errors(t *testing.T) {
+	var p testProvider
+	err := decodeJSON(t, `{"@type":"g:Map","@value":["years_of_exp","five"]}`, &p)
+	var decErr *DecodeError
+	require.True(t, errors.As(err, &decErr))
+	assert.Equal(t, "years_of_exp", decErr.Path)
+	assert.Equal(t, TypeString, decErr.Type)
+	assert.EqualError(t, err, `cannot decode g:String into int64 at years_of_exp: "five" is not a number`)
+
+	err = decodeJSON(t, `{"@type":"g:Map","@value":["sitter_id",{"@type":"g:List","@value":["s1","s2"]}]}`, &p)
+	assert.EqualError(t, err, "cannot decode g:List into string at sitter_id: got 2 values where one is expected")
+
+	var small []int8
+	err = decodeJSON(t, `{"@type":"g:List","@value":[{"@type":"g:Int32","@value":1},{"@type":"g:Int32","@value":300}]}`, &small)
+	assert.EqualError(t, err, "cannot decode g:Int32 into int8 at [1]: value overflows")
+
+	var n int32
+	err = decodeJSON(t, `{"@type":"g:Double","@value":1.5}`, &n)
+	assert.EqualError(t, err, "cannot decode g:Double into int32 at result: not an integer")
+
+	err = Decode(Attribute{}, p)
+	assert.Error(t, err)
+}
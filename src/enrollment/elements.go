@@ -0,0 +1,335 @@
+package enrollment
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Vertex is g:Vertex, properties are kept by key in the received order of values
+type Vertex struct {
+	ID         Attribute
+	Label      string
+	Properties map[string][]VertexProperty
+}
+
+type vertexRecord struct {
+	ID         Attribute              `json:"id"`
+	Label      string                 `json:"label"`
+	Properties map[string][]Attribute `json:"properties"`
+}
+
+func (v *Vertex) UnmarshalJSON(b []byte) error {
+	var rec vertexRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return err
+	}
+	*v = Vertex{ID: rec.ID, Label: rec.Label}
+	if len(rec.Properties) == 0 {
+		return nil
+	}
+	v.Properties = make(map[string][]VertexProperty, len(rec.Properties))
+	for key, values := range rec.Properties {
+		for _, a := range values {
+			switch a.Type {
+			case TypeVertexProperty:
+				v.Properties[key] = append(v.Properties[key], a.VertexPropertyValue())
+			case TypeMap:
+				// GraphSON 1 vertex property is an untyped object
+				m := a.MapValue()
+				v.Properties[key] = append(v.Properties[key], VertexProperty{ID: m.Get("id"), Label: key, Value: m.Get("value")})
+			default:
+				return fmt.Errorf("got %s where %s is expected", a.Type, TypeVertexProperty)
+			}
+		}
+	}
+	return nil
+}
+
+// Property returns the first vertex property with the key
+func (v Vertex) Property(key string) (VertexProperty, bool) {
+	props := v.Properties[key]
+	if len(props) == 0 {
+		return VertexProperty{}, false
+	}
+	return props[0], true
+}
+
+// PropertyValue returns value of the first vertex property with the key or empty Attribute
+func (v Vertex) PropertyValue(key string) Attribute {
+	p, _ := v.Property(key)
+	return p.Value
+}
+
+// Edge is g:Edge, inV and outV are ids of its vertices
+type Edge struct {
+	ID         Attribute
+	Label      string
+	InV        Attribute
+	InVLabel   string
+	OutV       Attribute
+	OutVLabel  string
+	Properties map[string]Property
+}
+
+type edgeRecord struct {
+	ID         Attribute            `json:"id"`
+	Label      string               `json:"label"`
+	InV        Attribute            `json:"inV"`
+	InVLabel   string               `json:"inVLabel"`
+	OutV       Attribute            `json:"outV"`
+	OutVLabel  string               `json:"outVLabel"`
+	Properties map[string]Attribute `json:"properties"`
+}
+
+func (e *Edge) UnmarshalJSON(b []byte) error {
+	var rec edgeRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return err
+	}
+	*e = Edge{
+		ID:        rec.ID,
+		Label:     rec.Label,
+		InV:       rec.InV,
+		InVLabel:  rec.InVLabel,
+		OutV:      rec.OutV,
+		OutVLabel: rec.OutVLabel,
+	}
+	if len(rec.Properties) == 0 {
+		return nil
+	}
+	e.Properties = make(map[string]Property, len(rec.Properties))
+	for key, a := range rec.Properties {
+		if a.Type != TypeProperty {
+			// GraphSON 1 edge property is a value itself
+			e.Properties[key] = Property{Key: key, Value: a}
+			continue
+		}
+		e.Properties[key] = a.PropertyValue()
+	}
+	return nil
+}
+
+// PropertyValue returns value of the edge property with the key or empty Attribute
+func (e Edge) PropertyValue(key string) Attribute {
+	return e.Properties[key].Value
+}
+
+// Path is g:Path, Labels[i] are step labels of Objects[i]
+type Path struct {
+	Labels  [][]string
+	Objects List
+}
+
+type pathRecord struct {
+	Labels  Attribute `json:"labels"`
+	Objects Attribute `json:"objects"`
+}
+
+func (p *Path) UnmarshalJSON(b []byte) error {
+	var rec pathRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return err
+	}
+	*p = Path{Objects: rec.Objects.ListValue()}
+	for _, labels := range rec.Labels.ListValue() {
+		var names []string
+		for _, l := range labels.ListValue() {
+			names = append(names, l.ToString())
+		}
+		p.Labels = append(p.Labels, names)
+	}
+	return nil
+}
+
+// BulkSet is g:BulkSet, every value is kept once with its bulk
+type BulkSet []BulkSetItem
+
+type BulkSetItem struct {
+	Value Attribute
+	Bulk  int64
+}
+
+// UnmarshalJSON reads a flat list of alternating values and g:Int64 bulks
+func (s *BulkSet) UnmarshalJSON(b []byte) error {
+	*s = nil
+	var items []Attribute
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	if len(items)%2 != 0 {
+		return fmt.Errorf("%s has a value without bulk", TypeBulkSet)
+	}
+	for i := 0; i < len(items); i += 2 {
+		*s = append(*s, BulkSetItem{Value: items[i], Bulk: items[i+1].Int64Value()})
+	}
+	return nil
+}
+
+// List expands bulks, so every value is repeated bulk times
+func (s BulkSet) List() List {
+	var l List
+	for _, item := range s {
+		for i := int64(0); i < item.Bulk; i++ {
+			l = append(l, item.Value)
+		}
+	}
+	return l
+}
+
+// Tree is g:Tree, every node keeps its children as a tree
+type Tree []TreeNode
+
+type TreeNode struct {
+	Key      Attribute
+	Children Tree
+}
+
+type treeNodeRecord struct {
+	Key   Attribute `json:"key"`
+	Value Attribute `json:"value"`
+}
+
+func (tr *Tree) UnmarshalJSON(b []byte) error {
+	*tr = nil
+	var nodes []treeNodeRecord
+	if err := json.Unmarshal(b, &nodes); err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		if n.Value.Type != TypeTree {
+			return fmt.Errorf("got %s where %s is expected", n.Value.Type, TypeTree)
+		}
+		*tr = append(*tr, TreeNode{Key: n.Key, Children: n.Value.TreeValue()})
+	}
+	return nil
+}
+
+// Traverser is g:Traverser, bulk is a number of equal results it stands for
+type Traverser struct {
+	Bulk  int64
+	Value Attribute
+}
+
+type traverserRecord struct {
+	Bulk  Attribute `json:"bulk"`
+	Value Attribute `json:"value"`
+}
+
+func (tr *Traverser) UnmarshalJSON(b []byte) error {
+	var rec traverserRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return err
+	}
+	*tr = Traverser{Bulk: rec.Bulk.Int64Value(), Value: rec.Value}
+	return nil
+}
+
+func toVertex(raw []byte, v *interface{}) error {
+	var val Vertex
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	*v = val
+	return nil
+}
+
+func toEdge(raw []byte, v *interface{}) error {
+	var val Edge
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	*v = val
+	return nil
+}
+
+func toPath(raw []byte, v *interface{}) error {
+	var val Path
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	*v = val
+	return nil
+}
+
+func toBulkSet(raw []byte, v *interface{}) error {
+	var val BulkSet
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	*v = val
+	return nil
+}
+
+func toTree(raw []byte, v *interface{}) error {
+	var val Tree
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	*v = val
+	return nil
+}
+
+func toTraverser(raw []byte, v *interface{}) error {
+	var val Traverser
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	*v = val
+	return nil
+}
+
+func (a Attribute) VertexValue() Vertex {
+	v, ok := a.Value.(Vertex)
+	if !ok {
+		return Vertex{}
+	}
+	return v
+}
+
+func (a Attribute) EdgeValue() Edge {
+	v, ok := a.Value.(Edge)
+	if !ok {
+		return Edge{}
+	}
+	return v
+}
+
+func (a Attribute) PathValue() Path {
+	v, ok := a.Value.(Path)
+	if !ok {
+		return Path{}
+	}
+	return v
+}
+
+// SetValue returns g:Set values, a set is kept as a List
+func (a Attribute) SetValue() List {
+	if a.Type != TypeSet {
+		return List{}
+	}
+	return a.ListValue()
+}
+
+func (a Attribute) BulkSetValue() BulkSet {
+	v, ok := a.Value.(BulkSet)
+	if !ok {
+		return BulkSet{}
+	}
+	return v
+}
+
+func (a Attribute) TreeValue() Tree {
+	v, ok := a.Value.(Tree)
+	if !ok {
+		return Tree{}
+	}
+	return v
+}
+
+func (a Attribute) TraverserValue() Traverser {
+	v, ok := a.Value.(Traverser)
+	if !ok {
+		return Traverser{}
+	}
+	return v
+}
@@ -0,0 +1,127 @@
+package enrollment
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttribute_UnmarshalJSON_Vertex(t *testing.T) {
+	js := []byte(`{"@type":"g:Vertex","@value":{"id":{"@type":"g:Int64","@value":1},"label":"provider","properties":{` +
+		`"sitter_id":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":10},"value":"s1","label":"sitter_id"}}],` +
+		`"years_of_exp":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":11},"value":{"@type":"g:Int32","@value":5},"label":"years_of_exp"}}]` +
+		`}}}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeVertex, a.Type)
+	v := a.VertexValue()
+	assert.Equal(t, int64(1), v.ID.Int64Value())
+	assert.Equal(t, "provider", v.Label)
+	assert.Len(t, v.Properties, 2)
+	assert.Equal(t, "s1", v.PropertyValue("sitter_id").StringValue())
+	assert.Equal(t, int32(5), v.PropertyValue("years_of_exp").Int32Value())
+	p, ok := v.Property("sitter_id")
+	require.True(t, ok)
+	assert.Equal(t, int64(10), p.ID.Int64Value())
+	_, ok = v.Property("gender")
+	assert.False(t, ok)
+}
+
+func TestAttribute_UnmarshalJSON_Edge(t *testing.T) {
+	js := []byte(`{"@type":"g:Edge","@value":{"id":{"@type":"g:Int64","@value":7},"label":"provides",` +
+		`"inVLabel":"service","outVLabel":"provider","inV":{"@type":"g:Int64","@value":2},"outV":{"@type":"g:Int64","@value":1},` +
+		`"properties":{"min_rate":{"@type":"g:Property","@value":{"key":"min_rate","value":{"@type":"g:Double","@value":12.5}}}}}}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeEdge, a.Type)
+	e := a.EdgeValue()
+	assert.Equal(t, int64(7), e.ID.Int64Value())
+	assert.Equal(t, "provides", e.Label)
+	assert.Equal(t, int64(2), e.InV.Int64Value())
+	assert.Equal(t, "service", e.InVLabel)
+	assert.Equal(t, int64(1), e.OutV.Int64Value())
+	assert.Equal(t, "provider", e.OutVLabel)
+	assert.Equal(t, 12.5, e.PropertyValue("min_rate").Float64Value())
+	assert.Equal(t, "min_rate", e.Properties["min_rate"].Key)
+}
+
+func TestAttribute_UnmarshalJSON_Path(t *testing.T) {
+	js := []byte(`{"@type":"g:Path","@value":{` +
+		`"labels":{"@type":"g:List","@value":[{"@type":"g:Set","@value":["p"]},{"@type":"g:Set","@value":[]}]},` +
+		`"objects":{"@type":"g:List","@value":["s1",{"@type":"g:Int32","@value":3}]}}}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypePath, a.Type)
+	p := a.PathValue()
+	assert.Equal(t, [][]string{{"p"}, nil}, p.Labels)
+	require.Len(t, p.Objects, 2)
+	assert.Equal(t, "s1", p.Objects[0].StringValue())
+	assert.Equal(t, int32(3), p.Objects[1].Int32Value())
+}
+
+func TestAttribute_UnmarshalJSON_Set(t *testing.T) {
+	js := []byte(`{"@type":"g:Set","@value":["childCare","petCare"]}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeSet, a.Type)
+	s := a.SetValue()
+	require.Len(t, s, 2)
+	assert.Equal(t, "childCare", s[0].StringValue())
+	assert.Equal(t, "petCare", s[1].StringValue())
+}
+
+func TestAttribute_UnmarshalJSON_BulkSet(t *testing.T) {
+	js := []byte(`{"@type":"g:BulkSet","@value":["s1",{"@type":"g:Int64","@value":2},"s2",{"@type":"g:Int64","@value":1}]}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeBulkSet, a.Type)
+	s := a.BulkSetValue()
+	require.Len(t, s, 2)
+	assert.Equal(t, "s1", s[0].Value.StringValue())
+	assert.Equal(t, int64(2), s[0].Bulk)
+	l := s.List()
+	require.Len(t, l, 3)
+	assert.Equal(t, "s1", l[1].StringValue())
+	assert.Equal(t, "s2", l[2].StringValue())
+}
+
+func TestAttribute_UnmarshalJSON_BulkSet_Odd(t *testing.T) {
+	js := []byte(`{"@type":"g:BulkSet","@value":["s1"]}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	assert.Error(t, err)
+}
+
+func TestAttribute_UnmarshalJSON_Tree(t *testing.T) {
+	js := []byte(`{"@type":"g:Tree","@value":[{"key":"78704","value":{"@type":"g:Tree","@value":[` +
+		`{"key":"s1","value":{"@type":"g:Tree","@value":[]}},` +
+		`{"key":"s2","value":{"@type":"g:Tree","@value":[]}}]}}]}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeTree, a.Type)
+	tr := a.TreeValue()
+	require.Len(t, tr, 1)
+	assert.Equal(t, "78704", tr[0].Key.StringValue())
+	require.Len(t, tr[0].Children, 2)
+	assert.Equal(t, "s2", tr[0].Children[1].Key.StringValue())
+	assert.Empty(t, tr[0].Children[1].Children)
+}
+
+func TestAttribute_UnmarshalJSON_Traverser(t *testing.T) {
+	js := []byte(`{"@type":"g:Traverser","@value":{"bulk":{"@type":"g:Int64","@value":3},"value":"s1"}}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeTraverser, a.Type)
+	tr := a.TraverserValue()
+	assert.Equal(t, int64(3), tr.Bulk)
+	assert.Equal(t, "s1", tr.Value.StringValue())
+}
@@ -0,0 +1,543 @@
+package enrollment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Marshal writes v as GraphSON 3, see MarshalVersion
+func Marshal(v interface{}) ([]byte, error) {
+	return MarshalVersion(v, GraphSONV3)
+}
+
+// MarshalVersion writes v as GraphSON of the version.
+// Besides Attribute and the types it holds, Go scalars, time.Time, time.Duration, *big.Int, slices, maps with string keys
+// and structs with `gremlin` field tags are written. Go maps are written with sorted keys, use Map to keep the order.
+// GraphSON 2 has no g:List, g:Set, g:Map and g:BulkSet, so lists and sets are written as json arrays
+// and maps as json objects.
+func MarshalVersion(v interface{}, version GraphSONVersion) ([]byte, error) {
+	if version != GraphSONV2 && version != GraphSONV3 {
+		return nil, fmt.Errorf("unsupported GraphSON version: %d", version)
+	}
+	e := &encoder{version: version}
+	if err := e.encode(v); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// MarshalJSON writes the attribute as GraphSON 3
+func (a Attribute) MarshalJSON() ([]byte, error) {
+	return Marshal(a)
+}
+
+type encoder struct {
+	version GraphSONVersion
+	buf     bytes.Buffer
+}
+
+func (e *encoder) encode(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		e.buf.WriteString(nullValue)
+		return nil
+	case Attribute:
+		return e.encodeAttribute(v)
+	case *Attribute:
+		if v == nil {
+			e.buf.WriteString(nullValue)
+			return nil
+		}
+		return e.encodeAttribute(*v)
+	case string:
+		return e.writeJSON(v)
+	case bool:
+		return e.writeJSON(v)
+	case int8:
+		return e.writeTyped(TypeInteger, int64(v))
+	case int16:
+		return e.writeTyped(TypeInteger, int64(v))
+	case int32:
+		return e.writeTyped(TypeInteger, int64(v))
+	case uint8:
+		return e.writeTyped(TypeInteger, int64(v))
+	case uint16:
+		return e.writeTyped(TypeInteger, int64(v))
+	case int:
+		return e.writeTyped(typeInt64, int64(v))
+	case int64:
+		return e.writeTyped(typeInt64, v)
+	case uint32:
+		return e.writeTyped(typeInt64, int64(v))
+	case uint:
+		return e.encodeUint(uint64(v))
+	case uint64:
+		return e.encodeUint(v)
+	case float32:
+		return e.writeTyped(TypeFloat, floatValueJSON(float64(v), 32))
+	case float64:
+		return e.writeTyped(TypeDouble, floatValueJSON(v, 64))
+	case time.Time:
+		return e.writeTyped(TypeDate, unixMillis(v))
+	case time.Duration:
+		return e.writeTyped(TypeDuration, FormatISODuration(v))
+	case Decimal:
+		return e.encodeAttribute(Attribute{Type: TypeBigDecimal, Value: v})
+	case *big.Int:
+		if v == nil {
+			e.buf.WriteString(nullValue)
+			return nil
+		}
+		return e.writeTyped(TypeBigInteger, json.RawMessage(v.String()))
+	case T:
+		return e.writeTyped(TypeT, string(v))
+	case Direction:
+		return e.writeTyped(TypeDirection, string(v))
+	case List:
+		return e.encodeList(TypeList, v)
+	case Map:
+		return e.encodeMap(v)
+	case Property:
+		return e.encodeProperty(v)
+	case VertexProperty:
+		return e.encodeVertexProperty(v)
+	case Vertex:
+		return e.encodeVertex(v)
+	case Edge:
+		return e.encodeEdge(v)
+	case Path:
+		return e.encodePath(v)
+	case BulkSet:
+		return e.encodeBulkSet(v)
+	case Tree:
+		return e.encodeTree(v)
+	case Traverser:
+		return e.encodeTraverser(v)
+	}
+	return e.encodeReflect(reflect.ValueOf(v))
+}
+
+// typeInt64 is the name GraphSON 3 uses for g:Long
+const typeInt64 DBType = "g:Int64"
+
+func (e *encoder) encodeAttribute(a Attribute) error {
+	switch a.Type {
+	case "", TypeString, TypeBoolean:
+		// an attribute without type holds a Go value
+		return e.encode(a.Value)
+	case TypeInteger:
+		n, ok := integerValue(a)
+		if !ok {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(TypeInteger, n)
+	case TypeLong:
+		n, ok := integerValue(a)
+		if !ok {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(typeInt64, n)
+	case TypeFloat:
+		f, ok := floatValue(a)
+		if !ok {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(TypeFloat, floatValueJSON(f, 32))
+	case TypeDouble:
+		f, ok := floatValue(a)
+		if !ok {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(TypeDouble, floatValueJSON(f, 64))
+	case TypeDate, TypeTimestamp:
+		tm, ok := a.Value.(time.Time)
+		if !ok {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(a.Type, unixMillis(tm))
+	case TypeUUID, TypeClass:
+		s, ok := a.Value.(string)
+		if !ok {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(a.Type, s)
+	case TypeSet:
+		l, ok := a.Value.(List)
+		if !ok {
+			return attributeValueError(a)
+		}
+		return e.encodeList(TypeSet, l)
+	case TypeBigDecimal:
+		d, ok := a.Value.(Decimal)
+		if !ok {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(TypeBigDecimal, json.RawMessage(d.String()))
+	case TypeBigInteger:
+		n, ok := a.Value.(*big.Int)
+		if !ok || n == nil {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(TypeBigInteger, json.RawMessage(n.String()))
+	case TypeByte:
+		n, ok := integerValue(a)
+		if !ok || n < math.MinInt8 || n > math.MaxInt8 {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(TypeByte, n)
+	case TypeInt16:
+		n, ok := integerValue(a)
+		if !ok || n < math.MinInt16 || n > math.MaxInt16 {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(TypeInt16, n)
+	case TypeByteBuffer:
+		b, ok := a.Value.([]byte)
+		if !ok {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(TypeByteBuffer, b)
+	case TypeChar:
+		r, ok := a.Value.(rune)
+		if !ok {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(TypeChar, string(r))
+	case TypeDuration:
+		d, ok := a.Value.(time.Duration)
+		if !ok {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(TypeDuration, FormatISODuration(d))
+	case TypeInstant, TypeLocalDate, TypeLocalDateTime, TypeOffsetDateTime:
+		tm, ok := a.Value.(time.Time)
+		if !ok {
+			return attributeValueError(a)
+		}
+		return e.writeTyped(a.Type, formatJavaTime(a.Type, tm))
+	}
+	if isCustomType(a.Type) {
+		// registered and raw types are written back with their type name,
+		// the value is plain json as TypeDecoder reads it
+		return e.writeTyped(a.Type, a.Value)
+	}
+	return e.encode(a.Value)
+}
+
+func attributeValueError(a Attribute) error {
+	return fmt.Errorf("cannot encode %T as %s", a.Value, a.Type)
+}
+
+func (e *encoder) encodeUint(v uint64) error {
+	if v > math.MaxInt64 {
+		return fmt.Errorf("cannot encode %d: overflows %s", v, typeInt64)
+	}
+	return e.writeTyped(typeInt64, int64(v))
+}
+
+// floatValueJSON returns a number or a string for NaN and infinities
+func floatValueJSON(f float64, bitSize int) interface{} {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return json.RawMessage(strconv.FormatFloat(f, 'g', -1, bitSize))
+}
+
+// unixMillis returns milliseconds since epoch without going through
+// UnixNano, which overflows outside the years 1678-2261
+func unixMillis(t time.Time) int64 {
+	return t.Unix()*1000 + int64(t.Nanosecond())/int64(time.Millisecond)
+}
+
+func (e *encoder) writeJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.buf.Write(b)
+	return nil
+}
+
+func (e *encoder) writeTyped(t DBType, value interface{}) error {
+	return e.writeWrapped(t, func() error {
+		return e.writeJSON(value)
+	})
+}
+
+// writeWrapped writes {"@type":t,"@value":...} with the value written by writeValue
+func (e *encoder) writeWrapped(t DBType, writeValue func() error) error {
+	e.buf.WriteString(`{"@type":`)
+	if err := e.writeJSON(string(t)); err != nil {
+		return err
+	}
+	e.buf.WriteString(`,"@value":`)
+	if err := writeValue(); err != nil {
+		return err
+	}
+	e.buf.WriteByte('}')
+	return nil
+}
+
+// writeArray writes n items as a json array
+func (e *encoder) writeArray(n int, writeItem func(i int) error) error {
+	e.buf.WriteByte('[')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		if err := writeItem(i); err != nil {
+			return err
+		}
+	}
+	e.buf.WriteByte(']')
+	return nil
+}
+
+// writeObject writes a json object with the keys and values written by writeValue
+func (e *encoder) writeObject(keys []string, writeValue func(i int) error) error {
+	e.buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		if err := e.writeJSON(key); err != nil {
+			return err
+		}
+		e.buf.WriteByte(':')
+		if err := writeValue(i); err != nil {
+			return err
+		}
+	}
+	e.buf.WriteByte('}')
+	return nil
+}
+
+func (e *encoder) encodeList(t DBType, items List) error {
+	writeItems := func() error {
+		return e.writeArray(len(items), func(i int) error {
+			return e.encode(items[i])
+		})
+	}
+	if e.version == GraphSONV2 {
+		return writeItems()
+	}
+	return e.writeWrapped(t, writeItems)
+}
+
+func (e *encoder) encodeMap(m Map) error {
+	entries := m.Entries()
+	if e.version == GraphSONV2 {
+		keys := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			keys = append(keys, entry.Key.ToString())
+		}
+		return e.writeObject(keys, func(i int) error {
+			return e.encode(entries[i].Value)
+		})
+	}
+	return e.writeWrapped(TypeMap, func() error {
+		return e.writeArray(len(entries)*2, func(i int) error {
+			if i%2 == 0 {
+				return e.encode(entries[i/2].Key)
+			}
+			return e.encode(entries[i/2].Value)
+		})
+	})
+}
+
+func (e *encoder) encodeProperty(p Property) error {
+	return e.writeWrapped(TypeProperty, func() error {
+		return e.writeObject([]string{"key", "value"}, func(i int) error {
+			if i == 0 {
+				return e.writeJSON(p.Key)
+			}
+			return e.encode(p.Value)
+		})
+	})
+}
+
+func (e *encoder) encodeVertexProperty(p VertexProperty) error {
+	return e.writeWrapped(TypeVertexProperty, func() error {
+		return e.writeObject([]string{"id", "value", "label"}, func(i int) error {
+			switch i {
+			case 0:
+				return e.encode(p.ID)
+			case 1:
+				return e.encode(p.Value)
+			}
+			return e.writeJSON(p.Label)
+		})
+	})
+}
+
+func (e *encoder) encodeVertex(v Vertex) error {
+	keys := make([]string, 0, len(v.Properties))
+	for k := range v.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return e.writeWrapped(TypeVertex, func() error {
+		return e.writeObject([]string{"id", "label", "properties"}, func(i int) error {
+			switch i {
+			case 0:
+				return e.encode(v.ID)
+			case 1:
+				return e.writeJSON(v.Label)
+			}
+			return e.writeObject(keys, func(i int) error {
+				props := v.Properties[keys[i]]
+				return e.writeArray(len(props), func(j int) error {
+					return e.encodeVertexProperty(props[j])
+				})
+			})
+		})
+	})
+}
+
+func (e *encoder) encodeEdge(edge Edge) error {
+	keys := make([]string, 0, len(edge.Properties))
+	for k := range edge.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := []string{"id", "label", "inVLabel", "outVLabel", "inV", "outV", "properties"}
+	return e.writeWrapped(TypeEdge, func() error {
+		return e.writeObject(fields, func(i int) error {
+			switch i {
+			case 0:
+				return e.encode(edge.ID)
+			case 1:
+				return e.writeJSON(edge.Label)
+			case 2:
+				return e.writeJSON(edge.InVLabel)
+			case 3:
+				return e.writeJSON(edge.OutVLabel)
+			case 4:
+				return e.encode(edge.InV)
+			case 5:
+				return e.encode(edge.OutV)
+			}
+			return e.writeObject(keys, func(i int) error {
+				return e.encodeProperty(edge.Properties[keys[i]])
+			})
+		})
+	})
+}
+
+func (e *encoder) encodePath(p Path) error {
+	labels := make(List, 0, len(p.Labels))
+	for _, names := range p.Labels {
+		set := make(List, 0, len(names))
+		for _, name := range names {
+			set = append(set, Attribute{Type: TypeString, Value: name})
+		}
+		labels = append(labels, Attribute{Type: TypeSet, Value: set})
+	}
+	return e.writeWrapped(TypePath, func() error {
+		return e.writeObject([]string{"labels", "objects"}, func(i int) error {
+			if i == 0 {
+				return e.encodeList(TypeList, labels)
+			}
+			return e.encodeList(TypeList, p.Objects)
+		})
+	})
+}
+
+func (e *encoder) encodeBulkSet(s BulkSet) error {
+	if e.version == GraphSONV2 {
+		return e.encodeList(TypeList, s.List())
+	}
+	return e.writeWrapped(TypeBulkSet, func() error {
+		return e.writeArray(len(s)*2, func(i int) error {
+			if i%2 == 0 {
+				return e.encode(s[i/2].Value)
+			}
+			return e.writeTyped(typeInt64, s[i/2].Bulk)
+		})
+	})
+}
+
+func (e *encoder) encodeTree(tr Tree) error {
+	return e.writeWrapped(TypeTree, func() error {
+		return e.writeArray(len(tr), func(i int) error {
+			return e.writeObject([]string{"key", "value"}, func(j int) error {
+				if j == 0 {
+					return e.encode(tr[i].Key)
+				}
+				return e.encodeTree(tr[i].Children)
+			})
+		})
+	})
+}
+
+func (e *encoder) encodeTraverser(tr Traverser) error {
+	return e.writeWrapped(TypeTraverser, func() error {
+		return e.writeObject([]string{"bulk", "value"}, func(i int) error {
+			if i == 0 {
+				return e.writeTyped(typeInt64, tr.Bulk)
+			}
+			return e.encode(tr.Value)
+		})
+	})
+}
+
+// encodeReflect writes pointers, slices, arrays, maps with string keys and structs
+func (e *encoder) encodeReflect(rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			e.buf.WriteString(nullValue)
+			return nil
+		}
+		return e.encode(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			e.buf.WriteString(nullValue)
+			return nil
+		}
+		items := make(List, rv.Len())
+		for i := range items {
+			items[i] = Attribute{Value: rv.Index(i).Interface()}
+		}
+		return e.encodeList(TypeList, items)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot encode %s: map key must be a string", rv.Type())
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		m := Map{}
+		for _, k := range keys {
+			value := rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key())).Interface()
+			m.add(Attribute{Type: TypeString, Value: k}, Attribute{Value: value})
+		}
+		return e.encodeMap(m)
+	case reflect.Struct:
+		m := Map{}
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			name, ok := fieldName(rt.Field(i))
+			if !ok {
+				continue
+			}
+			m.add(Attribute{Type: TypeString, Value: name}, Attribute{Value: rv.Field(i).Interface()})
+		}
+		return e.encodeMap(m)
+	}
+	return fmt.Errorf("cannot encode %s", rv.Type())
+}
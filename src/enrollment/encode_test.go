@@ -0,0 +1,143 @@
+package enrollment
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	js := `{"@type":"g:List","@value":[` +
+		`{"@type":"g:Map","@value":[{"@type":"g:T","@value":"id"},{"@type":"g:Int64","@value":1},"sitter_id","s1",` +
+		`{"@type":"g:Int32","@value":7},{"@type":"g:Set","@value":["childCare"]}]},` +
+		`{"@type":"g:Double","@value":1.25},{"@type":"g:Float","@value":4.5},true,null,` +
+		`{"@type":"g:Date","@value":1600000000000},{"@type":"g:Timestamp","@value":1600000000001},` +
+		`{"@type":"g:UUID","@value":"41d2e28a-20a4-4ab0-b379-d810dede3786"},{"@type":"g:Class","@value":"java.lang.String"},` +
+		`{"@type":"g:Direction","@value":"OUT"},` +
+		`{"@type":"g:Property","@value":{"key":"min_rate","value":{"@type":"g:Int32","@value":10}}},` +
+		`{"@type":"g:Vertex","@value":{"id":{"@type":"g:Int64","@value":1},"label":"provider","properties":{` +
+		`"sitter_id":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":10},"value":"s1","label":"sitter_id"}}]}}},` +
+		`{"@type":"g:Edge","@value":{"id":{"@type":"g:Int64","@value":7},"label":"provides","inVLabel":"service","outVLabel":"provider",` +
+		`"inV":{"@type":"g:Int64","@value":2},"outV":{"@type":"g:Int64","@value":1},` +
+		`"properties":{"min_rate":{"@type":"g:Property","@value":{"key":"min_rate","value":{"@type":"g:Double","@value":12.5}}}}}},` +
+		`{"@type":"g:Path","@value":{"labels":{"@type":"g:List","@value":[{"@type":"g:Set","@value":["p"]}]},"objects":{"@type":"g:List","@value":["s1"]}}},` +
+		`{"@type":"g:BulkSet","@value":["s1",{"@type":"g:Int64","@value":2}]},` +
+		`{"@type":"g:Tree","@value":[{"key":"78704","value":{"@type":"g:Tree","@value":[]}}]},` +
+		`{"@type":"g:Traverser","@value":{"bulk":{"@type":"g:Int64","@value":3},"value":"s1"}}` +
+		`]}`
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(js), &a))
+
+	b, err := Marshal(a)
+	require.NoError(t, err)
+	var decoded Attribute
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, a, decoded)
+
+	b2, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, string(b), string(b2))
+}
+
+func TestMarshal_DateRoundTrip(t *testing.T) {
+	for _, js := range []string{
+		`{"@type":"g:Date","@value":253402300799000}`,
+		`{"@type":"g:Date","@value":-62135596800000}`,
+		`{"@type":"g:Timestamp","@value":253402300799999}`,
+		`{"@type":"g:Timestamp","@value":-62135596799999}`,
+	} {
+		var a Attribute
+		require.NoError(t, json.Unmarshal([]byte(js), &a), js)
+		b, err := Marshal(a)
+		require.NoError(t, err)
+		assert.JSONEq(t, js, string(b))
+	}
+
+	b, err := Marshal(time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"@type":"g:Date","@value":253402300799000}`, string(b))
+	b, err = Marshal(time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"@type":"g:Date","@value":-62135596800000}`, string(b))
+}
+
+func TestMarshal_GoValues(t *testing.T) {
+	b, err := Marshal(map[string]interface{}{
+		"b": []int32{1},
+		"a": "x",
+		"c": int64(2),
+		"d": float32(0.5),
+		"e": 1.5,
+		"f": time.Unix(1600000000, 0),
+		"g": nil,
+		"h": TLabel,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `{"@type":"g:Map","@value":["a","x",`+
+		`"b",{"@type":"g:List","@value":[{"@type":"g:Int32","@value":1}]},`+
+		`"c",{"@type":"g:Int64","@value":2},"d",{"@type":"g:Float","@value":0.5},"e",{"@type":"g:Double","@value":1.5},`+
+		`"f",{"@type":"g:Date","@value":1600000000000},"g",null,"h",{"@type":"g:T","@value":"label"}]}`, string(b))
+}
+
+func TestMarshal_Struct(t *testing.T) {
+	nick := "ann"
+	p := testProvider{
+		ID:         1,
+		SitterID:   "s1",
+		YearsOfExp: 5,
+		AvgRank:    4.5,
+		Services:   []string{"childCare"},
+		LastActive: time.Unix(1600000000, 0).UTC(),
+		Nick:       &nick,
+		Ignored:    "x",
+	}
+	b, err := Marshal(p)
+	require.NoError(t, err)
+	var a Attribute
+	require.NoError(t, json.Unmarshal(b, &a))
+	assert.Equal(t, TypeMap, a.Type)
+	assert.Equal(t, 8, a.MapValue().Len())
+
+	var decoded testProvider
+	require.NoError(t, Decode(a, &decoded))
+	p.Ignored = ""
+	assert.Equal(t, p, decoded)
+}
+
+func TestMarshalVersion_V2(t *testing.T) {
+	m := NewMap(
+		MapEntry{Key: Attribute{Type: TypeString, Value: "sitter_id"}, Value: Attribute{Type: TypeString, Value: "s1"}},
+		MapEntry{Key: Attribute{Type: TypeInteger, Value: int32(7)}, Value: Attribute{Type: TypeList, Value: List{
+			{Type: TypeLong, Value: int64(1)},
+		}}},
+	)
+	b, err := MarshalVersion(m, GraphSONV2)
+	require.NoError(t, err)
+	assert.Equal(t, `{"sitter_id":"s1","7":[{"@type":"g:Int64","@value":1}]}`, string(b))
+
+	b, err = MarshalVersion(BulkSet{{Value: Attribute{Type: TypeString, Value: "s1"}, Bulk: 2}}, GraphSONV2)
+	require.NoError(t, err)
+	assert.Equal(t, `["s1","s1"]`, string(b))
+}
+
+func TestMarshal_SpecialFloats(t *testing.T) {
+	b, err := Marshal([]float64{math.NaN(), math.Inf(1), math.Inf(-1)})
+	require.NoError(t, err)
+	assert.Equal(t, `{"@type":"g:List","@value":[{"@type":"g:Double","@value":"NaN"},`+
+		`{"@type":"g:Double","@value":"Infinity"},{"@type":"g:Double","@value":"-Infinity"}]}`, string(b))
+}
+
+func TestMarshal_Errors(t *testing.T) {
+	_, err := Marshal(uint64(math.MaxUint64))
+	assert.Error(t, err)
+	_, err = Marshal(map[int]string{1: "x"})
+	assert.Error(t, err)
+	_, err = Marshal(Attribute{Type: TypeInteger, Value: "x"})
+	assert.Error(t, err)
+	_, err = MarshalVersion("x", GraphSONVersion(1))
+	assert.Error(t, err)
+}
@@ -2,68 +2,166 @@ package enrollment
 
 import (
 	"errors"
-	"strconv"
+	"fmt"
+	"time"
 
 	"github.com/northwesternmutual/grammes"
+	"github.com/northwesternmutual/grammes/query/operator"
 	p "github.com/northwesternmutual/grammes/query/predicate"
 	t "github.com/northwesternmutual/grammes/query/traversal"
 )
 
 const DefaultPageSize int32 = 20
 
+var now = time.Now
+
 type HourlyRateGRPCModel struct {
 	Min float32
 	Max float32
 }
 
+type SortField string
+
+const (
+	SortByAvgRank    SortField = "avg_rank"
+	SortByYearsOfExp SortField = "years_of_exp"
+	SortByMinRate    SortField = "min_rate"
+	SortByMaxRate    SortField = "max_rate"
+)
+
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// String renders direction as a bare gremlin token (asc/desc), not as a quoted string
+func (d SortDirection) String() string {
+	return string(d)
+}
+
+type SortGRPCModel struct {
+	Field     SortField
+	Direction SortDirection
+}
+
+type Projection string
+
+const (
+	// ProjectSitterIDs returns sitter_id of every found provider
+	ProjectSitterIDs Projection = ""
+	// ProjectProfiles returns provider profile with matched service, see ProviderResult
+	ProjectProfiles Projection = "profiles"
+)
+
+type CareTypeMatch string
+
+const (
+	// MatchAnyCareType finds providers offering at least one of requested care types
+	MatchAnyCareType CareTypeMatch = "any"
+	// MatchAllCareTypes finds providers offering every requested care type
+	MatchAllCareTypes CareTypeMatch = "all"
+)
+
 type GRPCModel struct {
 	PostalCode string
 	CareType   string
-	Gender     string
-	HourlyRate *HourlyRateGRPCModel
-	PageSize   int32
-	PageToken  string
+	// CareTypes are matched along with CareType according to CareTypeMatch, any by default
+	CareTypes     []string
+	CareTypeMatch CareTypeMatch
+	Gender        string
+	// MinYearsOfExp, MinAvgRank and ActiveWithinDays are ignored when zero
+	MinYearsOfExp    int32
+	MinAvgRank       float32
+	ActiveWithinDays int32
+	// MaxDistance expands search to zips near PostalCode, in miles
+	MaxDistance float32
+	HourlyRate  *HourlyRateGRPCModel
+	// Availability keeps providers free for the whole time window
+	Availability *TimeWindowGRPCModel
+	Sort         *SortGRPCModel
+	Projection   Projection
+	PageSize     int32
+	PageToken    string
 }
 
-func BuildQuery(req *GRPCModel) (t.String, error) {
+func BuildQuery(req *GRPCModel) (t.String, Bindings, error) {
 	var err error
 	g := grammes.Traversal()
 	if req == nil {
-		return g, errors.New("empty request")
+		return g, nil, errors.New("empty request")
 	}
+	b := make(Bindings)
 	// build core request
-	switch {
-	case isZIPModel(req):
-		g, err = providersFromZIP(g, req)
-	default:
-		g, err = providersFromService(g, req)
+	g, err = providers(g, req, b)
+	if err != nil {
+		return g, nil, err
 	}
+	// skip previous pages
+	g, err = seek(g, req, b)
 	if err != nil {
-		return g, err
+		return g, nil, err
 	}
 	// order result
-	//g = order(g, req)
+	g, err = order(g, req, b)
+	if err != nil {
+		return g, nil, err
+	}
 	// pagination
-	g, err = pagination(g, req)
+	g = pagination(g, req)
+	// in result
+	return forResult(g, req, b), b, nil
+}
+
+// providers selects providers matching request filters, provider -provides-> service edge is labeled as "e".
+// A provider is found once even if several of its services match.
+func providers(g t.String, req *GRPCModel, b Bindings) (t.String, error) {
+	g, err := providerServices(g, req, b)
 	if err != nil {
 		return g, err
 	}
-	// in result
-	return forResult(g, req), nil
+	if isDedupModel(req) {
+		g = g.Dedup()
+	}
+	return g, nil
+}
+
+// isDedupModel tells if a provider may be found by several services, then "e" is any of the matched edges
+func isDedupModel(req *GRPCModel) bool {
+	return len(careTypes(req)) != 1
+}
+
+// providerServices selects provider for every matched provides edge, the edge is labeled as "e"
+func providerServices(g t.String, req *GRPCModel, b Bindings) (t.String, error) {
+	switch {
+	case isRadiusModel(req) && !isZIPModel(req):
+		return g, errors.New("radius search requires postal code")
+	case isZIPModel(req):
+		return providersFromZIP(g, req, b)
+	default:
+		return providersFromService(g, req, b)
+	}
 }
 
 func isZIPModel(req *GRPCModel) bool {
 	return len(req.PostalCode) > 0
 }
 
-func providersFromZIP(g t.String, req *GRPCModel) (t.String, error) {
-	query := g.V().Has("zip", "name", req.PostalCode).In("lives")
-	query = addProviderFilter(query, req).As("p")
+func isRadiusModel(req *GRPCModel) bool {
+	return req.MaxDistance > 0
+}
+
+func providersFromZIP(g t.String, req *GRPCModel, b Bindings) (t.String, error) {
+	query := zips(g, req, b).In("lives")
+	query = addProviderFilter(query, req, b)
+	query = addAllServicesFilter(query, req, b)
+	query = addAvailabilityFilter(query, req, b).As("p")
 	// add limits for: provider -provides(and(limits...))-> service
-	query = query.OutE("provides")
+	query = query.OutE("provides").As("e")
 	limits := make([]t.String, 0, 3)
-	limits = appendRateLimits(limits, req)
-	limits = appendServiceLimit(limits, req)
+	limits = appendRateLimits(limits, req, b)
+	limits = appendServiceLimit(limits, req, b)
 	if len(limits) > 0 {
 		query = query.And(limits...)
 	}
@@ -71,50 +169,137 @@ func providersFromZIP(g t.String, req *GRPCModel) (t.String, error) {
 	return query.InV().Select("p"), nil
 }
 
-func providersFromService(g t.String, req *GRPCModel) (t.String, error) {
+func providersFromService(g t.String, req *GRPCModel, b Bindings) (t.String, error) {
 	query := g.V()
 	// select services
-	query = addServiceFilter(query, req)
+	query = addServiceFilter(query, req, b)
 	// add limits for: service <-provides(and(limits...))- provider
-	query = query.InE("provides")
+	query = query.InE("provides").As("e")
 	limits := make([]t.String, 0, 1)
-	limits = appendRateLimits(limits, req)
+	limits = appendRateLimits(limits, req, b)
 	if len(limits) > 0 {
 		query = query.And(limits...)
 	}
-	return query.OutV().HasLabel("provider"), nil
+	query = addProviderFilter(query.OutV(), req, b)
+	query = addAllServicesFilter(query, req, b)
+	return addAvailabilityFilter(query, req, b), nil
+}
+
+// zips selects requested zip and, for radius search, zips linked to it with near edges not farther than MaxDistance.
+// Distance to the requested zip is kept in the sack: zip -near(distance)- zip
+func zips(g t.String, req *GRPCModel, b Bindings) t.String {
+	postalCode := b.bind(postalCodeBinding, req.PostalCode)
+	if !isRadiusModel(req) {
+		return g.V().Has("zip", "name", postalCode)
+	}
+	maxDistance := b.bindFloat32(maxDistanceBinding, req.MaxDistance)
+	nearby := t.NewTraversal().
+		BothE("near").Has("distance", predicate("lte", maxDistance.String())).
+		Sack(operator.Sum).By("distance").
+		OtherV()
+	return g.WithSack(0).V().Has("zip", "name", postalCode).
+		Union(t.NewTraversal().Identity(), nearby).
+		Dedup()
 }
 
-func addProviderFilter(g t.String, req *GRPCModel) t.String {
+func addProviderFilter(g t.String, req *GRPCModel, b Bindings) t.String {
 	if len(req.Gender) > 0 {
-		return g.Has("provider", "gender", req.Gender)
+		g = g.Has("provider", "gender", b.bind(genderBinding, req.Gender))
+	} else {
+		g = g.HasLabel("provider")
+	}
+	if req.MinYearsOfExp > 0 {
+		minYears := b.bindInt(minYearsOfExpBinding, int(req.MinYearsOfExp))
+		g = g.Has("years_of_exp", predicate("gte", minYears.String()))
+	}
+	if req.MinAvgRank > 0 {
+		minAvgRank := b.bindFloat32(minAvgRankBinding, req.MinAvgRank)
+		g = g.Has("avg_rank", predicate("gte", minAvgRank.String()))
+	}
+	if req.ActiveWithinDays > 0 {
+		// last_active_at is unix milliseconds, the cutoff is truncated to the day
+		// so page and count queries of a search made around midnight find the same providers
+		since := now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -int(req.ActiveWithinDays))
+		activeSince := b.bindLong(activeSinceBinding, unixMillis(since))
+		g = g.Has("last_active_at", predicate("gte", activeSince.String()))
 	}
-	return g.HasLabel("provider")
+	return g
 }
 
-func addServiceFilter(g t.String, req *GRPCModel) t.String {
-	if len(req.CareType) > 0 {
-		return g.Has("service", req.CareType)
+func addServiceFilter(g t.String, req *GRPCModel, b Bindings) t.String {
+	if len(careTypes(req)) > 0 {
+		return g.Has("service", bindCareTypes(req, b))
 	}
 	return g.HasLabel("service")
 }
 
-func appendServiceLimit(limits []t.String, req *GRPCModel) []t.String {
-	if len(req.CareType) > 0 {
-		limits = append(limits, getRawHas("service", req.CareType))
+func appendServiceLimit(limits []t.String, req *GRPCModel, b Bindings) []t.String {
+	if len(careTypes(req)) > 0 {
+		limits = append(limits, getRawHas("service", bindCareTypes(req, b)))
 	}
 	return limits
 }
 
-func appendRateLimits(limits []t.String, req *GRPCModel) []t.String {
+// addAllServicesFilter keeps providers offering every requested care type within requested rates
+func addAllServicesFilter(g t.String, req *GRPCModel, b Bindings) t.String {
+	services := careTypes(req)
+	if req.CareTypeMatch != MatchAllCareTypes || len(services) < 2 {
+		return g
+	}
+	limits := make([]t.String, 0, len(services))
+	for i := range services {
+		serviceLimits := make([]t.String, 0, 3)
+		serviceLimits = append(serviceLimits, getRawHas("service", t.Custom(indexedBinding(careTypeBinding, i))))
+		serviceLimits = appendRateLimits(serviceLimits, req, b)
+		limits = append(limits, t.NewTraversal().OutE("provides").And(serviceLimits...).Raw())
+	}
+	return g.And(limits...)
+}
+
+// careTypes returns CareType and CareTypes without duplicates
+func careTypes(req *GRPCModel) []string {
+	if len(req.CareTypes) == 0 {
+		if len(req.CareType) == 0 {
+			return nil
+		}
+		return []string{req.CareType}
+	}
+	res := make([]string, 0, len(req.CareTypes)+1)
+	seen := make(map[string]bool, len(req.CareTypes)+1)
+	for _, ct := range append([]string{req.CareType}, req.CareTypes...) {
+		if len(ct) == 0 || seen[ct] {
+			continue
+		}
+		seen[ct] = true
+		res = append(res, ct)
+	}
+	return res
+}
+
+// bindCareTypes binds requested care types and returns value to match service with
+func bindCareTypes(req *GRPCModel, b Bindings) interface{} {
+	services := careTypes(req)
+	if len(services) == 1 {
+		return b.bind(careTypeBinding, services[0])
+	}
+	names := make([]interface{}, 0, len(services))
+	for i, ct := range services {
+		names = append(names, b.bind(indexedBinding(careTypeBinding, i), ct))
+	}
+	return p.Within(names...)
+}
+
+func appendRateLimits(limits []t.String, req *GRPCModel, b Bindings) []t.String {
 	if req.HourlyRate == nil {
 		return limits
 	}
 	if req.HourlyRate.Max > 0 {
-		limits = append(limits, getRawHas("max_rate", p.LessThanOrEqual(req.HourlyRate.Max)))
+		maxRate := b.bindFloat32(maxRateBinding, req.HourlyRate.Max)
+		limits = append(limits, getRawHas("max_rate", predicate("lte", maxRate.String())))
 	}
-	if req.HourlyRate.Max > 0 {
-		limits = append(limits, getRawHas("min_rate", p.GreaterThanOrEqual(req.HourlyRate.Min)))
+	if req.HourlyRate.Min > 0 {
+		minRate := b.bindFloat32(minRateBinding, req.HourlyRate.Min)
+		limits = append(limits, getRawHas("min_rate", predicate("gte", minRate.String())))
 	}
 	return limits
 }
@@ -123,36 +308,190 @@ func getRawHas(first interface{}, params ...interface{}) t.String {
 	return t.NewTraversal().Has(first, params...).Raw()
 }
 
-func order(q t.String, _ *GRPCModel) t.String {
-	//return q.Order().By("id", t.NewCustomTraversal("asc"))
-	return q.Order().By(t.NewCustomTraversal("id"))
+// seek skips providers up to the last one seen on the previous page
+func seek(q t.String, req *GRPCModel, b Bindings) (t.String, error) {
+	token, err := getPageToken(req)
+	if err != nil || token == nil {
+		return q, err
+	}
+	field, direction, err := getSort(req)
+	if err != nil {
+		return q, err
+	}
+	if token.Field != field || token.Direction != direction {
+		return q, ErrPageTokenMismatch
+	}
+	sitterID, err := bindToken(token.SitterID, lastSitterIDBinding, b)
+	if err != nil {
+		return q, err
+	}
+	if len(field) == 0 {
+		return q.Has("sitter_id", predicate("gt", sitterID)), nil
+	}
+	if token.Key == nil {
+		return q, ErrInvalidPageToken
+	}
+	key, err := bindToken(*token.Key, lastKeyBinding, b)
+	if err != nil {
+		return q, err
+	}
+	op := "gt"
+	if direction == SortDesc {
+		op = "lt"
+	}
+	// key is behind the last one or key is the same and sitter_id is behind the last one
+	sameKey := t.NewTraversal().And(
+		sortKeyHas(field, t.Custom(key), req, b),
+		getRawHas("sitter_id", predicate("gt", sitterID))).Raw()
+	return q.Or(sortKeyHas(field, predicate(op, key), req, b), sameKey), nil
 }
 
-func forResult(g t.String, _ *GRPCModel) t.String {
-	return g.Properties().HasKey("sitter_id").Value()
+// bindToken binds a page token value and returns the script reading it with the type kept in the token
+func bindToken(v tokenValue, name string, b Bindings) (string, error) {
+	if v.Type == TypeString {
+		return b.bind(name, v.Value).String(), nil
+	}
+	n, err := v.number()
+	if err != nil {
+		return "", err
+	}
+	return b.bindNumber(name, v.Type, n).String(), nil
 }
 
-func pagination(q t.String, req *GRPCModel) (t.String, error) {
-	pageToken, err := getPageToken(req)
+// order sorts providers by requested field; sitter_id is always the last key to keep pages stable
+func order(q t.String, req *GRPCModel, b Bindings) (t.String, error) {
+	field, direction, err := getSort(req)
 	if err != nil {
 		return q, err
 	}
-	pageSize := req.PageSize
-	if pageSize == 0 {
-		pageSize = DefaultPageSize
+	q = q.Order()
+	if len(field) > 0 {
+		q = q.By(sortKey(field, req, b), direction)
 	}
-	from := pageToken * pageSize
-	to := from + pageSize
-	return q.Range(from, to), nil
+	return q.By("sitter_id"), nil
 }
 
-func getPageToken(req *GRPCModel) (int32, error) {
-	if len(req.PageToken) == 0 {
-		return 0, nil
+// getSort returns requested sort field and direction, both are empty if no sort is requested
+func getSort(req *GRPCModel) (SortField, SortDirection, error) {
+	if req.Sort == nil || len(req.Sort.Field) == 0 {
+		return "", "", nil
 	}
-	token, err := strconv.ParseInt(req.PageToken, 10, 32)
-	if err != nil {
-		return 0, err
+	switch req.Sort.Field {
+	case SortByAvgRank, SortByYearsOfExp, SortByMinRate, SortByMaxRate:
+	default:
+		return "", "", fmt.Errorf("unknown sort field: %s", req.Sort.Field)
+	}
+	switch req.Sort.Direction {
+	case "":
+		return req.Sort.Field, SortAsc, nil
+	case SortAsc, SortDesc:
+		return req.Sort.Field, req.Sort.Direction, nil
+	}
+	return "", "", fmt.Errorf("unknown sort direction: %s", req.Sort.Direction)
+}
+
+// sortKey returns provider property name or traversal to the rate on the matched provides edge
+func sortKey(field SortField, req *GRPCModel, b Bindings) interface{} {
+	if !isEdgeSortField(field) {
+		return string(field)
+	}
+	if isDedupModel(req) {
+		return rateKey(field, req, b).Raw()
+	}
+	return t.NewTraversal().Select("e").Values(string(field)).Raw()
+}
+
+func sortKeyHas(field SortField, value interface{}, req *GRPCModel, b Bindings) t.String {
+	if !isEdgeSortField(field) {
+		return getRawHas(string(field), value)
+	}
+	if isDedupModel(req) {
+		return rateKey(field, req, b).Is(value).Raw()
+	}
+	return t.NewTraversal().Select("e").Has(string(field), value).Raw()
+}
+
+// rateKey aggregates the rate over all provides edges matched by the request:
+// a provider is sorted by the lowest min_rate and the highest max_rate of the matched services
+func rateKey(field SortField, req *GRPCModel, b Bindings) t.String {
+	limits := make([]t.String, 0, 3)
+	limits = appendRateLimits(limits, req, b)
+	limits = appendServiceLimit(limits, req, b)
+	edges := t.NewTraversal().OutE("provides")
+	if len(limits) > 0 {
+		edges = edges.And(limits...)
+	}
+	if field == SortByMaxRate {
+		return edges.Values(string(field)).Max()
+	}
+	return edges.Values(string(field)).Min()
+}
+
+func isEdgeSortField(field SortField) bool {
+	return field == SortByMinRate || field == SortByMaxRate
+}
+
+func predicate(op string, literal string) *p.Predicate {
+	pr := p.Predicate(op + "(" + literal + ")")
+	return &pr
+}
+
+func forResult(g t.String, req *GRPCModel, b Bindings) t.String {
+	var keys []string
+	var values []interface{}
+	// sort key is needed to build the next page token
+	if field, _, err := getSort(req); err == nil && len(field) > 0 {
+		keys = append(keys, sortKeyName)
+		values = append(values, sortKey(field, req, b))
+	}
+	if isRadiusModel(req) {
+		keys = append(keys, distanceName)
+		values = append(values, t.NewTraversal().Sack().Raw())
+	}
+	if req.Projection == ProjectProfiles {
+		keys, values = appendProfileColumns(keys, values)
+	}
+	if len(keys) == 0 {
+		return g.Properties().HasKey("sitter_id").Value()
+	}
+	g = g.Project("sitter_id", keys...).By("sitter_id")
+	for _, v := range values {
+		g = g.By(v)
+	}
+	return g
+}
+
+// appendProfileColumns adds ProviderResult fields to the result projection.
+// Properties are folded into lists, so a missing property gives an empty list instead of failing the traversal.
+func appendProfileColumns(keys []string, values []interface{}) ([]string, []interface{}) {
+	for _, key := range providerProfileKeys {
+		keys = append(keys, key)
+		values = append(values, t.NewTraversal().Properties(key).Fold().Raw())
+	}
+	for _, key := range serviceProfileKeys {
+		keys = append(keys, key)
+		values = append(values, t.NewTraversal().Select("e").Properties(key).Fold().Raw())
+	}
+	keys = append(keys, zipName)
+	values = append(values, t.NewTraversal().Out("lives").Properties("name").Fold().Raw())
+	return keys, values
+}
+
+// pagination takes one extra provider to find out if there is a next page
+func pagination(q t.String, req *GRPCModel) t.String {
+	return q.Limit(getPageSize(req) + 1)
+}
+
+func getPageSize(req *GRPCModel) int32 {
+	if req.PageSize == 0 {
+		return DefaultPageSize
+	}
+	return req.PageSize
+}
+
+func getPageToken(req *GRPCModel) (*pageToken, error) {
+	if len(req.PageToken) == 0 {
+		return nil, nil
 	}
-	return int32(token), nil
+	return decodePageToken(req.PageToken)
 }
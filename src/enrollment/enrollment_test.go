@@ -1,60 +1,525 @@
 package enrollment
 
 import (
+	"os"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/northwesternmutual/grammes"
+	"github.com/northwesternmutual/grammes/query/operator"
 	p "github.com/northwesternmutual/grammes/query/predicate"
 	t "github.com/northwesternmutual/grammes/query/traversal"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestMain(m *testing.M) {
+	if err := SetPageTokenSecret([]byte("test secret")); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
 func Test_BuildQuery_fromZIP(te *testing.T) {
 	g := grammes.Traversal()
-	expected := g.V().Has("zip", "name", "78704").
+	expected := g.V().Has("zip", "name", t.Custom("postalCode")).
 		In("lives").
 		HasLabel("provider").As("p").
-		OutE("provides").
-		And(t.NewTraversal().Has("max_rate", p.LessThanOrEqual(50)).Raw(),
-			t.NewTraversal().Has("min_rate", p.GreaterThanOrEqual(0)).Raw(),
-			t.NewTraversal().Has("service", "childCare").Raw()).
+		OutE("provides").As("e").
+		And(t.NewTraversal().Has("max_rate", predicate("lte", "Double.valueOf(maxRate)")).Raw(),
+			t.NewTraversal().Has("min_rate", predicate("gte", "Double.valueOf(minRate)")).Raw(),
+			t.NewTraversal().Has("service", t.Custom("careType")).Raw()).
 		InV().
 		Select("p").
-		//Order().By("sitter_id").
-		Range(0, 10).
+		Order().By("sitter_id").
+		Limit(11).
 		Properties().HasKey("sitter_id").Value()
 
-	query, err := BuildQuery(&GRPCModel{
+	query, bindings, err := BuildQuery(&GRPCModel{
 		PostalCode: "78704",
 		CareType:   "childCare",
-		HourlyRate: &HourlyRateGRPCModel{Min: 0, Max: 50},
+		HourlyRate: &HourlyRateGRPCModel{Min: 10, Max: 50},
 		PageSize:   10,
 	})
 	assert.NoError(te, err)
 
 	assert.Equal(te, expected.String(), query.String())
+	assert.Equal(te, Bindings{"postalCode": "78704", "careType": "childCare", "maxRate": "50", "minRate": "10"}, bindings)
 }
 
 func Test_BuildQuery_fromService(te *testing.T) {
+	token, err := encodePageToken(&pageToken{SitterID: tokenValue{Type: TypeString, Value: "s42"}})
+	assert.NoError(te, err)
+
 	g := grammes.Traversal()
 	expected := g.V().
-		Has("service", "childCare").
-		InE("provides").
-		And(t.NewTraversal().Has("max_rate", p.LessThanOrEqual(100)).Raw(),
-			t.NewTraversal().Has("min_rate", p.GreaterThanOrEqual(0)).Raw()).
+		Has("service", t.Custom("careType")).
+		InE("provides").As("e").
+		And(t.NewTraversal().Has("max_rate", predicate("lte", "Double.valueOf(maxRate)")).Raw()).
 		OutV().
 		HasLabel("provider").
-		//Order().By("sitter_id").
-		Range(20, 30).
+		Has("sitter_id", predicate("gt", "lastSitterID")).
+		Order().By("sitter_id").
+		Limit(11).
 		Properties().HasKey("sitter_id").Value()
 
-	query, err := BuildQuery(&GRPCModel{
+	query, bindings, err := BuildQuery(&GRPCModel{
 		CareType:   "childCare",
 		HourlyRate: &HourlyRateGRPCModel{Min: 0, Max: 100},
 		PageSize:   10,
-		PageToken:  "2",
+		PageToken:  token,
+	})
+	assert.NoError(te, err)
+
+	assert.Equal(te, expected.String(), query.String())
+	assert.Equal(te, Bindings{"careType": "childCare", "lastSitterID": "s42", "maxRate": "100"}, bindings)
+}
+
+func Test_BuildQuery_bindsRequestStrings(te *testing.T) {
+	req := &GRPCModel{
+		PostalCode: `78704").drop().V().has("zip","name","78704`,
+		Gender:     `female").drop().V("`,
+		CareType:   `childCare\")`,
+	}
+	query, bindings, err := BuildQuery(req)
+	assert.NoError(te, err)
+
+	assert.NotContains(te, query.String(), "drop()")
+	assert.NotContains(te, query.String(), "childCare")
+	assert.Equal(te, Bindings{
+		"postalCode": req.PostalCode,
+		"gender":     req.Gender,
+		"careType":   req.CareType,
+	}, bindings)
+
+	other, _, err := BuildQuery(&GRPCModel{PostalCode: "10001", Gender: "male", CareType: "petCare"})
+	assert.NoError(te, err)
+	assert.Equal(te, query.String(), other.String())
+}
+
+func Test_BuildQuery_bindsRequestNumbers(te *testing.T) {
+	search := func(minRate float32, days int, key string, sitterID int) (t.String, Bindings) {
+		token, err := encodePageToken(&pageToken{
+			Field:     SortByAvgRank,
+			Direction: SortDesc,
+			Key:       &tokenValue{Type: TypeFloat, Value: key},
+			SitterID:  tokenValue{Type: TypeInteger, Value: strconv.Itoa(sitterID)},
+		})
+		assert.NoError(te, err)
+		query, bindings, err := BuildQuery(&GRPCModel{
+			PostalCode:    "78704",
+			CareType:      "childCare",
+			MinYearsOfExp: int32(days / 10),
+			MinAvgRank:    minRate / 10,
+			MaxDistance:   minRate,
+			HourlyRate:    &HourlyRateGRPCModel{Min: minRate, Max: minRate * 2},
+			Availability: &TimeWindowGRPCModel{
+				From: time.Date(2020, time.October, days, 9, 0, 0, 0, time.UTC),
+				To:   time.Date(2020, time.October, days, 17, 0, 0, 0, time.UTC),
+			},
+			ActiveWithinDays: int32(days),
+			Sort:             &SortGRPCModel{Field: SortByAvgRank, Direction: SortDesc},
+			PageToken:        token,
+		})
+		assert.NoError(te, err)
+		return query, bindings
+	}
+
+	query, bindings := search(10.5, 12, "4.5", 42)
+	other, otherBindings := search(20, 27, "3.25", 7)
+	assert.Equal(te, query.String(), other.String())
+	assert.NotEqual(te, bindings, otherBindings)
+	assert.Equal(te, "10.5", bindings["minRate"])
+	assert.Equal(te, "4.5", bindings["lastKey"])
+	assert.Equal(te, "42", bindings["lastSitterID"])
+	assert.Equal(te, "1602493200000", bindings["windowFrom"])
+	for _, v := range []string{"10.5", "4.5", "42", "1602493200000"} {
+		assert.NotContains(te, query.String(), v)
+	}
+}
+
+func Test_BuildQuery_bindsPageTokenStrings(te *testing.T) {
+	token, err := encodePageToken(&pageToken{
+		Field:     SortByAvgRank,
+		Direction: SortAsc,
+		Key:       &tokenValue{Type: TypeString, Value: `${System.exit(0)}`},
+		SitterID:  tokenValue{Type: TypeString, Value: `s1"+"`},
+	})
+	assert.NoError(te, err)
+
+	query, bindings, err := BuildQuery(&GRPCModel{
+		CareType:  "childCare",
+		Sort:      &SortGRPCModel{Field: SortByAvgRank},
+		PageToken: token,
+	})
+	assert.NoError(te, err)
+
+	assert.NotContains(te, query.String(), "System")
+	assert.NotContains(te, query.String(), "s1")
+	assert.Equal(te, Bindings{"careType": "childCare", "lastKey": `${System.exit(0)}`, "lastSitterID": `s1"+"`}, bindings)
+}
+
+func Test_BuildQuery_fromZIP_sorted(te *testing.T) {
+	g := grammes.Traversal()
+	expected := g.V().Has("zip", "name", t.Custom("postalCode")).
+		In("lives").
+		HasLabel("provider").As("p").
+		OutE("provides").As("e").
+		And(t.NewTraversal().Has("service", t.Custom("careType")).Raw()).
+		InV().
+		Select("p").
+		Order().By("avg_rank", SortDesc).By("sitter_id").
+		Limit(21).
+		Project("sitter_id", "sort_key").By("sitter_id").By("avg_rank")
+
+	query, _, err := BuildQuery(&GRPCModel{
+		PostalCode: "78704",
+		CareType:   "childCare",
+		Sort:       &SortGRPCModel{Field: SortByAvgRank, Direction: SortDesc},
+	})
+	assert.NoError(te, err)
+
+	assert.Equal(te, expected.String(), query.String())
+}
+
+func Test_BuildQuery_fromService_sortedByRate(te *testing.T) {
+	g := grammes.Traversal()
+	expected := g.V().
+		Has("service", t.Custom("careType")).
+		InE("provides").As("e").
+		OutV().
+		HasLabel("provider").
+		Order().By(t.NewTraversal().Select("e").Values("min_rate").Raw(), SortAsc).By("sitter_id").
+		Limit(11).
+		Project("sitter_id", "sort_key").By("sitter_id").By(t.NewTraversal().Select("e").Values("min_rate").Raw())
+
+	query, _, err := BuildQuery(&GRPCModel{
+		CareType: "childCare",
+		Sort:     &SortGRPCModel{Field: SortByMinRate},
+		PageSize: 10,
+	})
+	assert.NoError(te, err)
+
+	assert.Equal(te, expected.String(), query.String())
+}
+
+func Test_BuildQuery_unknownSort(te *testing.T) {
+	_, _, err := BuildQuery(&GRPCModel{
+		CareType: "childCare",
+		Sort:     &SortGRPCModel{Field: "distance"},
+	})
+	assert.Error(te, err)
+
+	_, _, err = BuildQuery(&GRPCModel{
+		CareType: "childCare",
+		Sort:     &SortGRPCModel{Field: SortByMaxRate, Direction: "up"},
+	})
+	assert.Error(te, err)
+}
+
+func Test_BuildQuery_sortedPageToken(te *testing.T) {
+	token, err := encodePageToken(&pageToken{
+		Field:     SortByMinRate,
+		Direction: SortDesc,
+		Key:       &tokenValue{Type: TypeFloat, Value: "12.5"},
+		SitterID:  tokenValue{Type: TypeInteger, Value: "42"},
+	})
+	assert.NoError(te, err)
+
+	g := grammes.Traversal()
+	expected := g.V().
+		Has("service", t.Custom("careType")).
+		InE("provides").As("e").
+		OutV().
+		HasLabel("provider").
+		Or(t.NewTraversal().Select("e").Has("min_rate", predicate("lt", "Float.valueOf(lastKey)")).Raw(),
+			t.NewTraversal().And(
+				t.NewTraversal().Select("e").Has("min_rate", t.Custom("Float.valueOf(lastKey)")).Raw(),
+				t.NewTraversal().Has("sitter_id", predicate("gt", "Integer.valueOf(lastSitterID)")).Raw()).Raw()).
+		Order().By(t.NewTraversal().Select("e").Values("min_rate").Raw(), SortDesc).By("sitter_id").
+		Limit(11).
+		Project("sitter_id", "sort_key").By("sitter_id").By(t.NewTraversal().Select("e").Values("min_rate").Raw())
+
+	query, _, err := BuildQuery(&GRPCModel{
+		CareType:  "childCare",
+		Sort:      &SortGRPCModel{Field: SortByMinRate, Direction: SortDesc},
+		PageSize:  10,
+		PageToken: token,
+	})
+	assert.NoError(te, err)
+
+	assert.Equal(te, expected.String(), query.String())
+}
+
+func Test_BuildQuery_pageTokenMismatch(te *testing.T) {
+	token, err := encodePageToken(&pageToken{SitterID: tokenValue{Type: TypeString, Value: "s42"}})
+	assert.NoError(te, err)
+
+	_, _, err = BuildQuery(&GRPCModel{
+		CareType:  "childCare",
+		Sort:      &SortGRPCModel{Field: SortByAvgRank},
+		PageToken: token,
+	})
+	assert.Equal(te, ErrPageTokenMismatch, err)
+}
+
+func Test_BuildQuery_invalidPageToken(te *testing.T) {
+	token, err := encodePageToken(&pageToken{SitterID: tokenValue{Type: TypeString, Value: "s42"}})
+	assert.NoError(te, err)
+
+	for _, pageToken := range []string{"2", "abc.def", token + "x", "x" + token} {
+		_, _, err = BuildQuery(&GRPCModel{
+			CareType:  "childCare",
+			PageToken: pageToken,
+		})
+		assert.Equal(te, ErrInvalidPageToken, err, pageToken)
+	}
+}
+
+func Test_BuildQuery_radius(te *testing.T) {
+	g := grammes.Traversal()
+	expected := g.WithSack(0).V().Has("zip", "name", t.Custom("postalCode")).
+		Union(t.NewTraversal().Identity(),
+			t.NewTraversal().BothE("near").Has("distance", predicate("lte", "Double.valueOf(maxDistance)")).Sack(operator.Sum).By("distance").OtherV()).
+		Dedup().
+		In("lives").
+		HasLabel("provider").As("p").
+		OutE("provides").As("e").
+		And(t.NewTraversal().Has("service", t.Custom("careType")).Raw()).
+		InV().
+		Select("p").
+		Order().By("sitter_id").
+		Limit(11).
+		Project("sitter_id", "distance").By("sitter_id").By(t.NewTraversal().Sack().Raw())
+
+	query, _, err := BuildQuery(&GRPCModel{
+		PostalCode:  "78704",
+		MaxDistance: 5,
+		CareType:    "childCare",
+		PageSize:    10,
+	})
+	assert.NoError(te, err)
+
+	assert.Equal(te, expected.String(), query.String())
+}
+
+func Test_BuildQuery_radiusWithoutZIP(te *testing.T) {
+	_, _, err := BuildQuery(&GRPCModel{
+		MaxDistance: 5,
+		CareType:    "childCare",
+	})
+	assert.Error(te, err)
+}
+
+func Test_BuildQuery_profiles(te *testing.T) {
+	g := grammes.Traversal()
+	expected := g.V().
+		Has("service", t.Custom("careType")).
+		InE("provides").As("e").
+		OutV().
+		HasLabel("provider").
+		Order().By("sitter_id").
+		Limit(21).
+		Project("sitter_id", "gender", "avg_rank", "years_of_exp", "service", "min_rate", "max_rate", "zip").
+		By("sitter_id").
+		By(t.NewTraversal().Properties("gender").Fold().Raw()).
+		By(t.NewTraversal().Properties("avg_rank").Fold().Raw()).
+		By(t.NewTraversal().Properties("years_of_exp").Fold().Raw()).
+		By(t.NewTraversal().Select("e").Properties("service").Fold().Raw()).
+		By(t.NewTraversal().Select("e").Properties("min_rate").Fold().Raw()).
+		By(t.NewTraversal().Select("e").Properties("max_rate").Fold().Raw()).
+		By(t.NewTraversal().Out("lives").Properties("name").Fold().Raw())
+
+	query, _, err := BuildQuery(&GRPCModel{
+		CareType:   "childCare",
+		Projection: ProjectProfiles,
+	})
+	assert.NoError(te, err)
+
+	assert.Equal(te, expected.String(), query.String())
+}
+
+func Test_BuildQuery_minRateOnly(te *testing.T) {
+	g := grammes.Traversal()
+	expected := g.V().
+		Has("service", t.Custom("careType")).
+		InE("provides").As("e").
+		And(t.NewTraversal().Has("min_rate", predicate("gte", "Double.valueOf(minRate)")).Raw()).
+		OutV().
+		HasLabel("provider").
+		Order().By("sitter_id").
+		Limit(21).
+		Properties().HasKey("sitter_id").Value()
+
+	query, _, err := BuildQuery(&GRPCModel{
+		CareType:   "childCare",
+		HourlyRate: &HourlyRateGRPCModel{Min: 15},
+	})
+	assert.NoError(te, err)
+
+	assert.Equal(te, expected.String(), query.String())
+}
+
+func Test_BuildQuery_anyCareType(te *testing.T) {
+	g := grammes.Traversal()
+	expected := g.V().Has("zip", "name", t.Custom("postalCode")).
+		In("lives").
+		HasLabel("provider").As("p").
+		OutE("provides").As("e").
+		And(t.NewTraversal().Has("max_rate", predicate("lte", "Double.valueOf(maxRate)")).Raw(),
+			t.NewTraversal().Has("service", p.Within(t.Custom("careType0"), t.Custom("careType1"))).Raw()).
+		InV().
+		Select("p").
+		Dedup().
+		Order().By("sitter_id").
+		Limit(21).
+		Properties().HasKey("sitter_id").Value()
+
+	query, bindings, err := BuildQuery(&GRPCModel{
+		PostalCode: "78704",
+		CareType:   "childCare",
+		CareTypes:  []string{"petCare", "childCare"},
+		HourlyRate: &HourlyRateGRPCModel{Max: 30},
+	})
+	assert.NoError(te, err)
+
+	assert.Equal(te, expected.String(), query.String())
+	assert.Equal(te, Bindings{"postalCode": "78704", "careType0": "childCare", "careType1": "petCare", "maxRate": "30"}, bindings)
+}
+
+func Test_BuildQuery_sortedCareTypes(te *testing.T) {
+	token, err := encodePageToken(&pageToken{
+		Field:     SortByMaxRate,
+		Direction: SortDesc,
+		Key:       &tokenValue{Type: TypeFloat, Value: "25"},
+		SitterID:  tokenValue{Type: TypeInteger, Value: "42"},
+	})
+	assert.NoError(te, err)
+
+	// the rate is aggregated over the matched edges as "e" is any of them after dedup
+	maxRate := func() t.String {
+		return t.NewTraversal().OutE("provides").And(
+			t.NewTraversal().Has("max_rate", predicate("lte", "Double.valueOf(maxRate)")).Raw(),
+			t.NewTraversal().Has("service", p.Within(t.Custom("careType0"), t.Custom("careType1"))).Raw()).
+			Values("max_rate").Max()
+	}
+	g := grammes.Traversal()
+	expected := g.V().Has("zip", "name", t.Custom("postalCode")).
+		In("lives").
+		HasLabel("provider").As("p").
+		OutE("provides").As("e").
+		And(t.NewTraversal().Has("max_rate", predicate("lte", "Double.valueOf(maxRate)")).Raw(),
+			t.NewTraversal().Has("service", p.Within(t.Custom("careType0"), t.Custom("careType1"))).Raw()).
+		InV().
+		Select("p").
+		Dedup().
+		Or(maxRate().Is(predicate("lt", "Float.valueOf(lastKey)")).Raw(),
+			t.NewTraversal().And(
+				maxRate().Is(t.Custom("Float.valueOf(lastKey)")).Raw(),
+				t.NewTraversal().Has("sitter_id", predicate("gt", "Integer.valueOf(lastSitterID)")).Raw()).Raw()).
+		Order().By(maxRate().Raw(), SortDesc).By("sitter_id").
+		Limit(21).
+		Project("sitter_id", "sort_key").By("sitter_id").By(maxRate().Raw())
+
+	query, _, err := BuildQuery(&GRPCModel{
+		PostalCode: "78704",
+		CareType:   "childCare",
+		CareTypes:  []string{"petCare"},
+		HourlyRate: &HourlyRateGRPCModel{Max: 30},
+		Sort:       &SortGRPCModel{Field: SortByMaxRate, Direction: SortDesc},
+		PageToken:  token,
+	})
+	assert.NoError(te, err)
+
+	assert.Equal(te, expected.String(), query.String())
+}
+
+func Test_BuildQuery_allCareTypes(te *testing.T) {
+	rates := func(service string) t.String {
+		return t.NewTraversal().OutE("provides").And(
+			t.NewTraversal().Has("service", t.Custom(service)).Raw(),
+			t.NewTraversal().Has("max_rate", predicate("lte", "Double.valueOf(maxRate)")).Raw()).Raw()
+	}
+	g := grammes.Traversal()
+	expected := g.V().
+		Has("service", p.Within(t.Custom("careType0"), t.Custom("careType1"))).
+		InE("provides").As("e").
+		And(t.NewTraversal().Has("max_rate", predicate("lte", "Double.valueOf(maxRate)")).Raw()).
+		OutV().
+		HasLabel("provider").
+		And(rates("careType0"), rates("careType1")).
+		Dedup().
+		Order().By("sitter_id").
+		Limit(21).
+		Properties().HasKey("sitter_id").Value()
+
+	query, bindings, err := BuildQuery(&GRPCModel{
+		CareTypes:     []string{"childCare", "petCare"},
+		CareTypeMatch: MatchAllCareTypes,
+		HourlyRate:    &HourlyRateGRPCModel{Max: 30},
 	})
 	assert.NoError(te, err)
 
 	assert.Equal(te, expected.String(), query.String())
+	assert.Equal(te, Bindings{"careType0": "childCare", "careType1": "petCare", "maxRate": "30"}, bindings)
+}
+
+func Test_BuildQuery_providerFilters(te *testing.T) {
+	now = func() time.Time { return time.Date(2020, time.October, 10, 15, 4, 5, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	req := &GRPCModel{
+		CareType:         "childCare",
+		Gender:           "female",
+		MinYearsOfExp:    3,
+		MinAvgRank:       4.5,
+		ActiveWithinDays: 30,
+	}
+	providerFilters := func(q t.String) t.String {
+		return q.Has("provider", "gender", t.Custom("gender")).
+			Has("years_of_exp", predicate("gte", "Integer.valueOf(minYearsOfExp)")).
+			Has("avg_rank", predicate("gte", "Double.valueOf(minAvgRank)")).
+			Has("last_active_at", predicate("gte", "Long.valueOf(activeSince)"))
+	}
+
+	g := grammes.Traversal()
+	expected := providerFilters(g.V().Has("zip", "name", t.Custom("postalCode")).In("lives")).As("p").
+		OutE("provides").As("e").
+		And(t.NewTraversal().Has("service", t.Custom("careType")).Raw()).
+		InV().
+		Select("p").
+		Order().By("sitter_id").
+		Limit(21).
+		Properties().HasKey("sitter_id").Value()
+
+	req.PostalCode = "78704"
+	query, _, err := BuildQuery(req)
+	assert.NoError(te, err)
+	assert.Equal(te, expected.String(), query.String())
+
+	expected = providerFilters(g.V().
+		Has("service", t.Custom("careType")).
+		InE("provides").As("e").
+		OutV()).
+		Order().By("sitter_id").
+		Limit(21).
+		Properties().HasKey("sitter_id").Value()
+
+	req.PostalCode = ""
+	query, bindings, err := BuildQuery(req)
+	assert.NoError(te, err)
+	assert.Equal(te, expected.String(), query.String())
+	assert.Equal(te, Bindings{
+		"careType":      "childCare",
+		"gender":        "female",
+		"minYearsOfExp": "3",
+		"minAvgRank":    "4.5",
+		"activeSince":   "1599696000000",
+	}, bindings)
+
+	// the cutoff does not change within the day
+	now = func() time.Time { return time.Date(2020, time.October, 10, 23, 59, 59, 0, time.UTC) }
+	_, countBindings, err := BuildCountQuery(req)
+	assert.NoError(te, err)
+	assert.Equal(te, "1599696000000", countBindings["activeSince"])
 }
@@ -0,0 +1,569 @@
+package enrollment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// layouts of java.time values, parsing accepts fractional seconds after seconds
+const (
+	localDateLayout      = "2006-01-02"
+	localDateTimeLayout  = "2006-01-02T15:04:05.999999999"
+	offsetDateTimeLayout = "2006-01-02T15:04:05.999999999Z07:00"
+)
+
+// java.time writes no seconds when they are zero
+var (
+	localDateTimeLayouts  = []string{"2006-01-02T15:04:05", "2006-01-02T15:04"}
+	offsetDateTimeLayouts = []string{"2006-01-02T15:04:05Z07:00", "2006-01-02T15:04Z07:00"}
+)
+
+// numberText returns json number as it is written, a number written as a string is accepted as well
+func numberText(raw []byte) string {
+	return string(bytes.Trim(bytes.TrimSpace(raw), `"`))
+}
+
+func toDecimal(raw []byte, v *interface{}) error {
+	d, err := ParseDecimal(numberText(raw))
+	if err != nil {
+		return err
+	}
+	*v = d
+	return nil
+}
+
+func toBigInt(raw []byte, v *interface{}) error {
+	s := numberText(raw)
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("wrong %s value: %s", TypeBigInteger, s)
+	}
+	*v = n
+	return nil
+}
+
+func toByte(raw []byte, v *interface{}) error {
+	var val int8
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	*v = val
+	return nil
+}
+
+// toByteBuffer reads base64 string
+func toByteBuffer(raw []byte, v *interface{}) error {
+	var val []byte
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	*v = val
+	return nil
+}
+
+func toChar(raw []byte, v *interface{}) error {
+	var val string
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	r, size := utf8.DecodeRuneInString(val)
+	if size == 0 || size != len(val) || r == utf8.RuneError {
+		return fmt.Errorf("wrong %s value: %q", TypeChar, val)
+	}
+	*v = r
+	return nil
+}
+
+func toInt16(raw []byte, v *interface{}) error {
+	var val int16
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	*v = val
+	return nil
+}
+
+// toDuration reads ISO-8601 duration as java.time.Duration writes it, e.g. PT8H6M12.345S
+func toDuration(raw []byte, v *interface{}) error {
+	var val string
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	d, err := ParseISODuration(val)
+	if err != nil {
+		return err
+	}
+	*v = d
+	return nil
+}
+
+// toInstant reads ISO-8601 instant, e.g. 2016-12-14T16:39:19.349Z
+func toInstant(raw []byte, v *interface{}) error {
+	tm, err := parseTime(raw, TypeInstant, time.RFC3339Nano)
+	if err != nil {
+		return err
+	}
+	*v = tm.UTC()
+	return nil
+}
+
+// toLocalDate reads a date without time zone, it is kept as UTC midnight
+func toLocalDate(raw []byte, v *interface{}) error {
+	tm, err := parseTime(raw, TypeLocalDate, localDateLayout)
+	if err != nil {
+		return err
+	}
+	*v = tm
+	return nil
+}
+
+// toLocalDateTime reads a date and time without time zone, it is kept as UTC
+func toLocalDateTime(raw []byte, v *interface{}) error {
+	tm, err := parseTime(raw, TypeLocalDateTime, localDateTimeLayouts...)
+	if err != nil {
+		return err
+	}
+	*v = tm
+	return nil
+}
+
+// toOffsetDateTime reads a date and time with offset, the offset is kept as a fixed zone
+func toOffsetDateTime(raw []byte, v *interface{}) error {
+	tm, err := parseTime(raw, TypeOffsetDateTime, offsetDateTimeLayouts...)
+	if err != nil {
+		return err
+	}
+	*v = fixedZone(tm)
+	return nil
+}
+
+func parseTime(raw []byte, t DBType, layouts ...string) (time.Time, error) {
+	var val string
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return time.Time{}, err
+	}
+	for _, layout := range layouts {
+		if tm, err := time.Parse(layout, val); err == nil {
+			return tm, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("wrong %s value: %s", t, val)
+}
+
+// formatJavaTime writes time of a java.time type the way java.time writes it
+func formatJavaTime(t DBType, tm time.Time) string {
+	switch t {
+	case TypeLocalDate:
+		return tm.Format(localDateLayout)
+	case TypeLocalDateTime:
+		return tm.Format(localDateTimeLayout)
+	case TypeOffsetDateTime:
+		return tm.Format(offsetDateTimeLayout)
+	}
+	return tm.UTC().Format(time.RFC3339Nano)
+}
+
+// fixedZone replaces location of tm by its offset, so the same offset is read the same way in any local zone
+func fixedZone(tm time.Time) time.Time {
+	_, offset := tm.Zone()
+	if offset == 0 {
+		return tm.UTC()
+	}
+	return tm.In(time.FixedZone("", offset))
+}
+
+var isoDurationPattern = regexp.MustCompile(`^([-+]?)P(?:([-+]?[0-9]+)D)?(?:T(?:([-+]?[0-9]+)H)?(?:([-+]?[0-9]+)M)?(?:([-+]?)([0-9]+)(?:[.,]([0-9]{0,9}))?S)?)?$`)
+
+var errDurationRange = errors.New("duration out of range")
+
+// ParseISODuration reads ISO-8601 duration of days, hours, minutes and seconds as java.time.Duration.parse does
+func ParseISODuration(s string) (time.Duration, error) {
+	upper := strings.ToUpper(s)
+	m := isoDurationPattern.FindStringSubmatch(upper)
+	if m == nil || strings.HasSuffix(upper, "T") || len(m[2]+m[3]+m[4]+m[6]) == 0 {
+		return 0, fmt.Errorf("wrong %s value: %s", TypeDuration, s)
+	}
+	var d time.Duration
+	for _, part := range []struct {
+		value string
+		unit  time.Duration
+	}{{m[2], 24 * time.Hour}, {m[3], time.Hour}, {m[4], time.Minute}, {m[6], time.Second}} {
+		if len(part.value) == 0 {
+			continue
+		}
+		n, err := strconv.ParseInt(part.value, 10, 64)
+		if err != nil {
+			return 0, errDurationRange
+		}
+		if part.unit == time.Second && m[5] == "-" {
+			n = -n
+		}
+		if d, err = addDuration(d, n, part.unit); err != nil {
+			return 0, err
+		}
+	}
+	if len(m[7]) > 0 {
+		nanos, _ := strconv.ParseInt(m[7]+strings.Repeat("0", 9-len(m[7])), 10, 64)
+		// the sign of seconds is the sign of their fraction as well
+		if m[5] == "-" {
+			nanos = -nanos
+		}
+		var err error
+		if d, err = addDuration(d, nanos, time.Nanosecond); err != nil {
+			return 0, err
+		}
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+func addDuration(d time.Duration, n int64, unit time.Duration) (time.Duration, error) {
+	if n > math.MaxInt64/int64(unit) || n < math.MinInt64/int64(unit) {
+		return 0, errDurationRange
+	}
+	v := time.Duration(n) * unit
+	if (v > 0 && d > math.MaxInt64-v) || (v < 0 && d < math.MinInt64-v) {
+		return 0, errDurationRange
+	}
+	return d + v, nil
+}
+
+// FormatISODuration writes ISO-8601 duration as java.time.Duration does, e.g. PT8H6M12.345S
+func FormatISODuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString("PT")
+	if h := d / time.Hour; h != 0 {
+		b.WriteString(strconv.FormatInt(int64(h), 10))
+		b.WriteByte('H')
+	}
+	if m := d % time.Hour / time.Minute; m != 0 {
+		b.WriteString(strconv.FormatInt(int64(m), 10))
+		b.WriteByte('M')
+	}
+	secs := d % time.Minute
+	if secs == 0 {
+		return b.String()
+	}
+	if secs < 0 {
+		b.WriteByte('-')
+		secs = -secs
+	}
+	b.WriteString(strconv.FormatInt(int64(secs/time.Second), 10))
+	if nanos := secs % time.Second; nanos != 0 {
+		b.WriteByte('.')
+		b.WriteString(strings.TrimRight(fmt.Sprintf("%09d", nanos), "0"))
+	}
+	b.WriteByte('S')
+	return b.String()
+}
+
+// DecimalValue returns exact value of gx:BigDecimal
+func (a Attribute) DecimalValue() Decimal {
+	v, ok := a.Value.(Decimal)
+	if !ok {
+		return Decimal{}
+	}
+	return v
+}
+
+// BigIntValue returns value of gx:BigInteger, nil for other types
+func (a Attribute) BigIntValue() *big.Int {
+	v, ok := a.Value.(*big.Int)
+	if !ok {
+		return nil
+	}
+	return v
+}
+
+// ByteValue returns value of gx:Byte, which is signed as java byte
+func (a Attribute) ByteValue() int8 {
+	v, ok := a.Value.(int8)
+	if !ok {
+		return 0
+	}
+	return v
+}
+
+func (a Attribute) ByteBufferValue() []byte {
+	v, ok := a.Value.([]byte)
+	if !ok {
+		return nil
+	}
+	return v
+}
+
+// CharValue returns value of gx:Char, the type is checked as rune is the same go type as g:Int32 value
+func (a Attribute) CharValue() rune {
+	if a.Type != TypeChar {
+		return 0
+	}
+	return a.Int32Value()
+}
+
+func (a Attribute) Int16Value() int16 {
+	v, ok := a.Value.(int16)
+	if !ok {
+		return 0
+	}
+	return v
+}
+
+func (a Attribute) DurationValue() time.Duration {
+	v, ok := a.Value.(time.Duration)
+	if !ok {
+		return 0
+	}
+	return v
+}
+
+// InstantValue returns value of gx:Instant in UTC
+func (a Attribute) InstantValue() time.Time {
+	return a.typedTimeValue(TypeInstant)
+}
+
+// LocalDateValue returns value of gx:LocalDate as UTC midnight
+func (a Attribute) LocalDateValue() time.Time {
+	return a.typedTimeValue(TypeLocalDate)
+}
+
+// LocalDateTimeValue returns value of gx:LocalDateTime as UTC time
+func (a Attribute) LocalDateTimeValue() time.Time {
+	return a.typedTimeValue(TypeLocalDateTime)
+}
+
+// OffsetDateTimeValue returns value of gx:OffsetDateTime in a zone of its offset
+func (a Attribute) OffsetDateTimeValue() time.Time {
+	return a.typedTimeValue(TypeOffsetDateTime)
+}
+
+func (a Attribute) typedTimeValue(t DBType) time.Time {
+	if a.Type != t {
+		return time.Time{}
+	}
+	return a.TimeValue()
+}
+
+// streamTyped calls fn for every item of g:List records, in strict mode items of other types than t are an error
+func streamTyped(recs [][]byte, t DBType, target string, strict bool, fn func(a Attribute)) error {
+	var n int
+	return StreamRecords(recs, func(a Attribute) error {
+		if strict && a.Type != t {
+			return fmt.Errorf("item %d: %w", n, attributeError(a, target, ""))
+		}
+		n++
+		fn(a)
+		return nil
+	})
+}
+
+// UnmarshalDecimalList joins g:List records of gx:BigDecimal into one list, items of other types are read as zero
+func UnmarshalDecimalList(recs [][]byte) ([]Decimal, error) {
+	return decimalList(recs, false)
+}
+
+// UnmarshalDecimalListStrict is UnmarshalDecimalList failing on items which are not gx:BigDecimal
+func UnmarshalDecimalListStrict(recs [][]byte) ([]Decimal, error) {
+	return decimalList(recs, true)
+}
+
+func decimalList(recs [][]byte, strict bool) ([]Decimal, error) {
+	var items []Decimal
+	err := streamTyped(recs, TypeBigDecimal, "Decimal", strict, func(a Attribute) {
+		items = append(items, a.DecimalValue())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UnmarshalBigIntList joins g:List records of gx:BigInteger into one list, items of other types are read as nil
+func UnmarshalBigIntList(recs [][]byte) ([]*big.Int, error) {
+	return bigIntList(recs, false)
+}
+
+// UnmarshalBigIntListStrict is UnmarshalBigIntList failing on items which are not gx:BigInteger
+func UnmarshalBigIntListStrict(recs [][]byte) ([]*big.Int, error) {
+	return bigIntList(recs, true)
+}
+
+func bigIntList(recs [][]byte, strict bool) ([]*big.Int, error) {
+	var items []*big.Int
+	err := streamTyped(recs, TypeBigInteger, "*big.Int", strict, func(a Attribute) {
+		items = append(items, a.BigIntValue())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UnmarshalByteList joins g:List records of gx:Byte into one list, items of other types are read as zero
+func UnmarshalByteList(recs [][]byte) ([]int8, error) {
+	return byteList(recs, false)
+}
+
+// UnmarshalByteListStrict is UnmarshalByteList failing on items which are not gx:Byte
+func UnmarshalByteListStrict(recs [][]byte) ([]int8, error) {
+	return byteList(recs, true)
+}
+
+func byteList(recs [][]byte, strict bool) ([]int8, error) {
+	var items []int8
+	err := streamTyped(recs, TypeByte, "int8", strict, func(a Attribute) {
+		items = append(items, a.ByteValue())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UnmarshalByteBufferList joins g:List records of gx:ByteBuffer into one list, items of other types are read as nil
+func UnmarshalByteBufferList(recs [][]byte) ([][]byte, error) {
+	return byteBufferList(recs, false)
+}
+
+// UnmarshalByteBufferListStrict is UnmarshalByteBufferList failing on items which are not gx:ByteBuffer
+func UnmarshalByteBufferListStrict(recs [][]byte) ([][]byte, error) {
+	return byteBufferList(recs, true)
+}
+
+func byteBufferList(recs [][]byte, strict bool) ([][]byte, error) {
+	var items [][]byte
+	err := streamTyped(recs, TypeByteBuffer, "[]byte", strict, func(a Attribute) {
+		items = append(items, a.ByteBufferValue())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UnmarshalCharList joins g:List records of gx:Char into one list, items of other types are read as zero
+func UnmarshalCharList(recs [][]byte) ([]rune, error) {
+	return charList(recs, false)
+}
+
+// UnmarshalCharListStrict is UnmarshalCharList failing on items which are not gx:Char
+func UnmarshalCharListStrict(recs [][]byte) ([]rune, error) {
+	return charList(recs, true)
+}
+
+func charList(recs [][]byte, strict bool) ([]rune, error) {
+	var items []rune
+	err := streamTyped(recs, TypeChar, "rune", strict, func(a Attribute) {
+		items = append(items, a.CharValue())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UnmarshalInt16List joins g:List records of gx:Int16 into one list, items of other types are read as zero
+func UnmarshalInt16List(recs [][]byte) ([]int16, error) {
+	return int16List(recs, false)
+}
+
+// UnmarshalInt16ListStrict is UnmarshalInt16List failing on items which are not gx:Int16
+func UnmarshalInt16ListStrict(recs [][]byte) ([]int16, error) {
+	return int16List(recs, true)
+}
+
+func int16List(recs [][]byte, strict bool) ([]int16, error) {
+	var items []int16
+	err := streamTyped(recs, TypeInt16, "int16", strict, func(a Attribute) {
+		items = append(items, a.Int16Value())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UnmarshalDurationList joins g:List records of gx:Duration into one list, items of other types are read as zero
+func UnmarshalDurationList(recs [][]byte) ([]time.Duration, error) {
+	return durationList(recs, false)
+}
+
+// UnmarshalDurationListStrict is UnmarshalDurationList failing on items which are not gx:Duration
+func UnmarshalDurationListStrict(recs [][]byte) ([]time.Duration, error) {
+	return durationList(recs, true)
+}
+
+func durationList(recs [][]byte, strict bool) ([]time.Duration, error) {
+	var items []time.Duration
+	err := streamTyped(recs, TypeDuration, "time.Duration", strict, func(a Attribute) {
+		items = append(items, a.DurationValue())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UnmarshalInstantList joins g:List records of gx:Instant into one list, items of other types are read as zero time
+func UnmarshalInstantList(recs [][]byte) ([]time.Time, error) {
+	return timeList(recs, TypeInstant, false)
+}
+
+// UnmarshalInstantListStrict is UnmarshalInstantList failing on items which are not gx:Instant
+func UnmarshalInstantListStrict(recs [][]byte) ([]time.Time, error) {
+	return timeList(recs, TypeInstant, true)
+}
+
+// UnmarshalLocalDateList joins g:List records of gx:LocalDate into one list, items of other types are read as zero time
+func UnmarshalLocalDateList(recs [][]byte) ([]time.Time, error) {
+	return timeList(recs, TypeLocalDate, false)
+}
+
+// UnmarshalLocalDateListStrict is UnmarshalLocalDateList failing on items which are not gx:LocalDate
+func UnmarshalLocalDateListStrict(recs [][]byte) ([]time.Time, error) {
+	return timeList(recs, TypeLocalDate, true)
+}
+
+// UnmarshalLocalDateTimeList joins g:List records of gx:LocalDateTime into one list, items of other types are read as zero time
+func UnmarshalLocalDateTimeList(recs [][]byte) ([]time.Time, error) {
+	return timeList(recs, TypeLocalDateTime, false)
+}
+
+// UnmarshalLocalDateTimeListStrict is UnmarshalLocalDateTimeList failing on items which are not gx:LocalDateTime
+func UnmarshalLocalDateTimeListStrict(recs [][]byte) ([]time.Time, error) {
+	return timeList(recs, TypeLocalDateTime, true)
+}
+
+// UnmarshalOffsetDateTimeList joins g:List records of gx:OffsetDateTime into one list, items of other types are read as zero time
+func UnmarshalOffsetDateTimeList(recs [][]byte) ([]time.Time, error) {
+	return timeList(recs, TypeOffsetDateTime, false)
+}
+
+// UnmarshalOffsetDateTimeListStrict is UnmarshalOffsetDateTimeList failing on items which are not gx:OffsetDateTime
+func UnmarshalOffsetDateTimeListStrict(recs [][]byte) ([]time.Time, error) {
+	return timeList(recs, TypeOffsetDateTime, true)
+}
+
+func timeList(recs [][]byte, t DBType, strict bool) ([]time.Time, error) {
+	var items []time.Time
+	err := streamTyped(recs, t, "time.Time", strict, func(a Attribute) {
+		items = append(items, a.typedTimeValue(t))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,258 @@
+package enrollment
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const extendedResult = `{"@type":"g:List","@value":[` +
+	`{"@type":"gx:BigDecimal","@value":123456789012345678901234567890.125},` +
+	`{"@type":"gx:BigInteger","@value":-123456789012345678901234567890},` +
+	`{"@type":"gx:Byte","@value":-3},` +
+	`{"@type":"gx:ByteBuffer","@value":"aGVsbG8="},` +
+	`{"@type":"gx:Char","@value":"€"},` +
+	`{"@type":"gx:Int16","@value":1200},` +
+	`{"@type":"gx:Duration","@value":"PT8H6M12.345S"},` +
+	`{"@type":"gx:Instant","@value":"2016-12-14T16:39:19.349Z"},` +
+	`{"@type":"gx:LocalDate","@value":"2016-01-01"},` +
+	`{"@type":"gx:LocalDateTime","@value":"2016-01-01T12:30"},` +
+	`{"@type":"gx:OffsetDateTime","@value":"2007-12-03T10:15:30+01:00"}` +
+	`]}`
+
+func TestUnmarshal_ExtendedTypes(t *testing.T) {
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(extendedResult), &a))
+	items := a.ListValue()
+	require.Len(t, items, 11)
+
+	assert.Equal(t, TypeBigDecimal, items[0].Type)
+	assert.Equal(t, "123456789012345678901234567890.125", items[0].DecimalValue().String())
+	assert.Equal(t, "-123456789012345678901234567890", items[1].BigIntValue().String())
+	assert.Equal(t, int8(-3), items[2].ByteValue())
+	assert.Equal(t, []byte("hello"), items[3].ByteBufferValue())
+	assert.Equal(t, '€', items[4].CharValue())
+	assert.Equal(t, int16(1200), items[5].Int16Value())
+	assert.Equal(t, 8*time.Hour+6*time.Minute+12345*time.Millisecond, items[6].DurationValue())
+	assert.Equal(t, time.Date(2016, 12, 14, 16, 39, 19, 349000000, time.UTC), items[7].InstantValue())
+	assert.Equal(t, time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC), items[8].LocalDateValue())
+	assert.Equal(t, time.Date(2016, 1, 1, 12, 30, 0, 0, time.UTC), items[9].LocalDateTimeValue())
+	offset := items[10].OffsetDateTimeValue()
+	assert.True(t, time.Date(2007, 12, 3, 9, 15, 30, 0, time.UTC).Equal(offset))
+	_, seconds := offset.Zone()
+	assert.Equal(t, 3600, seconds)
+
+	// accessors check the type
+	assert.Equal(t, rune(0), Attribute{Type: TypeInteger, Value: int32(8364)}.CharValue())
+	assert.True(t, items[8].InstantValue().IsZero())
+
+	var streamed List
+	require.NoError(t, StreamRecords([][]byte{[]byte(extendedResult)}, func(a Attribute) error {
+		streamed = append(streamed, a)
+		return nil
+	}))
+	assert.Equal(t, items, streamed)
+}
+
+func TestUnmarshal_ExtendedTypesErrors(t *testing.T) {
+	tests := []string{
+		`{"@type":"gx:BigDecimal","@value":"x"}`,
+		`{"@type":"gx:BigInteger","@value":1.5}`,
+		`{"@type":"gx:Byte","@value":200}`,
+		`{"@type":"gx:Char","@value":"ab"}`,
+		`{"@type":"gx:Char","@value":""}`,
+		`{"@type":"gx:Int16","@value":40000}`,
+		`{"@type":"gx:Duration","@value":"8h"}`,
+		`{"@type":"gx:Instant","@value":"2016-12-14"}`,
+		`{"@type":"gx:LocalDate","@value":"2016-13-01"}`,
+	}
+	for _, js := range tests {
+		var a Attribute
+		assert.Error(t, json.Unmarshal([]byte(js), &a), js)
+	}
+}
+
+func TestISODuration(t *testing.T) {
+	tests := []struct {
+		s string
+		d time.Duration
+		f string
+	}{
+		{"PT0S", 0, "PT0S"},
+		{"PT8H6M12.345S", 8*time.Hour + 6*time.Minute + 12345*time.Millisecond, "PT8H6M12.345S"},
+		{"P2DT3H", 51 * time.Hour, "PT51H"},
+		{"PT-6H3M", -6*time.Hour + 3*time.Minute, "PT-5H-57M"},
+		{"-PT6H3M", -6*time.Hour - 3*time.Minute, "PT-6H-3M"},
+		{"PT-0.5S", -500 * time.Millisecond, "PT-0.5S"},
+		{"pt1m", time.Minute, "PT1M"},
+		{"PT0.000000001S", time.Nanosecond, "PT0.000000001S"},
+	}
+	for _, test := range tests {
+		d, err := ParseISODuration(test.s)
+		require.NoError(t, err, test.s)
+		assert.Equal(t, test.d, d, test.s)
+		assert.Equal(t, test.f, FormatISODuration(d), test.s)
+	}
+	for _, s := range []string{"", "P", "PT", "P1DT", "PT1.S1", "P1H", "PT1000000000H"} {
+		_, err := ParseISODuration(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestMarshal_ExtendedTypes(t *testing.T) {
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(extendedResult), &a))
+	b, err := Marshal(a)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"@type":"g:List","@value":[`+
+		`{"@type":"gx:BigDecimal","@value":123456789012345678901234567890.125},`+
+		`{"@type":"gx:BigInteger","@value":-123456789012345678901234567890},`+
+		`{"@type":"gx:Byte","@value":-3},`+
+		`{"@type":"gx:ByteBuffer","@value":"aGVsbG8="},`+
+		`{"@type":"gx:Char","@value":"€"},`+
+		`{"@type":"gx:Int16","@value":1200},`+
+		`{"@type":"gx:Duration","@value":"PT8H6M12.345S"},`+
+		`{"@type":"gx:Instant","@value":"2016-12-14T16:39:19.349Z"},`+
+		`{"@type":"gx:LocalDate","@value":"2016-01-01"},`+
+		`{"@type":"gx:LocalDateTime","@value":"2016-01-01T12:30:00"},`+
+		`{"@type":"gx:OffsetDateTime","@value":"2007-12-03T10:15:30+01:00"}`+
+		`]}`, string(b))
+	// the decimal is written exactly, not as float64
+	assert.Contains(t, string(b), "123456789012345678901234567890.125")
+
+	var decoded Attribute
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, a, decoded)
+
+	b, err = Marshal(map[string]interface{}{"rate": NewDecimal(1250, 2), "n": big.NewInt(7), "d": time.Minute})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"@type":"g:Map","@value":["d",{"@type":"gx:Duration","@value":"PT1M"},`+
+		`"n",{"@type":"gx:BigInteger","@value":7},"rate",{"@type":"gx:BigDecimal","@value":12.50}]}`, string(b))
+}
+
+func TestGraphBinary_ExtendedTypes(t *testing.T) {
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(extendedResult), &a))
+	b, err := MarshalGraphBinary(a)
+	require.NoError(t, err)
+	decoded, err := UnmarshalGraphBinary(b)
+	require.NoError(t, err)
+	assert.Equal(t, a, decoded)
+
+	tests := []struct {
+		a   Attribute
+		bin []byte
+	}{
+		{Attribute{Type: TypeBigInteger, Value: big.NewInt(-129)}, []byte{0x23, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7f}},
+		{Attribute{Type: TypeBigInteger, Value: big.NewInt(128)}, []byte{0x23, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x80}},
+		{Attribute{Type: TypeBigDecimal, Value: NewDecimal(-1, 2)}, []byte{0x22, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x01, 0xff}},
+		{Attribute{Type: TypeByte, Value: int8(-1)}, []byte{0x24, 0x00, 0xff}},
+		{Attribute{Type: TypeInt16, Value: int16(258)}, []byte{0x26, 0x00, 0x01, 0x02}},
+		{Attribute{Type: TypeChar, Value: '€'}, []byte{0x80, 0x00, 0xe2, 0x82, 0xac}},
+		{Attribute{Type: TypeDuration, Value: -1500 * time.Millisecond},
+			[]byte{0x81, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe, 0x1d, 0xcd, 0x65, 0x00}},
+		{Attribute{Type: TypeLocalDate, Value: time.Date(2016, 1, 2, 0, 0, 0, 0, time.UTC)}, []byte{0x84, 0x00, 0x00, 0x00, 0x07, 0xe0, 0x01, 0x02}},
+	}
+	for _, test := range tests {
+		b, err := MarshalGraphBinary(test.a)
+		require.NoError(t, err)
+		assert.Equal(t, test.bin, b, test.a.Type)
+
+		decoded, err := UnmarshalGraphBinary(test.bin)
+		require.NoError(t, err)
+		assert.Equal(t, graphSON(t, test.a), graphSON(t, decoded))
+	}
+}
+
+// graphSON writes the attribute as GraphSON to compare values which hold big.Int
+func graphSON(t *testing.T, a Attribute) string {
+	b, err := Marshal(a)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestUnmarshalExtendedLists(t *testing.T) {
+	recs := [][]byte{
+		[]byte(`{"@type":"g:List","@value":[{"@type":"gx:BigDecimal","@value":12.50},{"@type":"g:Double","@value":1.5}]}`),
+	}
+	decimals, err := UnmarshalDecimalList(recs)
+	require.NoError(t, err)
+	require.Len(t, decimals, 2)
+	assert.Equal(t, "12.50", decimals[0].String())
+	assert.Equal(t, "0", decimals[1].String())
+	_, err = UnmarshalDecimalListStrict(recs)
+	assert.EqualError(t, err, "item 1: cannot read g:Double as Decimal")
+	decimals, err = UnmarshalDecimalListStrict(recs[:0])
+	require.NoError(t, err)
+	assert.Empty(t, decimals)
+
+	recs = [][]byte{[]byte(`{"@type":"g:List","@value":[{"@type":"gx:Int16","@value":1},{"@type":"gx:Int16","@value":-2}]}`)}
+	shorts, err := UnmarshalInt16ListStrict(recs)
+	require.NoError(t, err)
+	assert.Equal(t, []int16{1, -2}, shorts)
+	_, err = UnmarshalByteListStrict(recs)
+	assert.Error(t, err)
+	signed, err := UnmarshalByteList(recs)
+	require.NoError(t, err)
+	assert.Equal(t, []int8{0, 0}, signed)
+
+	recs = [][]byte{[]byte(`{"@type":"g:List","@value":[{"@type":"gx:LocalDate","@value":"2016-01-01"},{"@type":"gx:Instant","@value":"2016-12-14T16:39:19Z"}]}`)}
+	dates, err := UnmarshalLocalDateList(recs)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC), {}}, dates)
+	_, err = UnmarshalLocalDateListStrict(recs)
+	assert.EqualError(t, err, "item 1: cannot read gx:Instant as time.Time")
+
+	recs = [][]byte{[]byte(`{"@type":"g:List","@value":[{"@type":"gx:Duration","@value":"PT1S"},{"@type":"gx:Char","@value":"x"},` +
+		`{"@type":"gx:BigInteger","@value":5},{"@type":"gx:ByteBuffer","@value":"AQ=="}]}`)}
+	durations, err := UnmarshalDurationList(recs)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, 0, 0, 0}, durations)
+	chars, err := UnmarshalCharList(recs)
+	require.NoError(t, err)
+	assert.Equal(t, []rune{0, 'x', 0, 0}, chars)
+	ints, err := UnmarshalBigIntList(recs)
+	require.NoError(t, err)
+	require.Len(t, ints, 4)
+	assert.Equal(t, "5", ints[2].String())
+	buffers, err := UnmarshalByteBufferList(recs)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{nil, nil, nil, {1}}, buffers)
+}
+
+func TestDecode_ExtendedTypes(t *testing.T) {
+	js := `{"@type":"g:Map","@value":["rate",{"@type":"gx:BigDecimal","@value":12.50},"payout",{"@type":"g:Double","@value":0.1},` +
+		`"total",{"@type":"gx:BigInteger","@value":123456789012345678901234567890},"count",{"@type":"gx:Int16","@value":3},` +
+		`"grade",{"@type":"gx:Char","@value":"A"},"wait",{"@type":"gx:Duration","@value":"PT1M"},"day",{"@type":"gx:LocalDate","@value":"2016-01-01"}]}`
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(js), &a))
+
+	var v struct {
+		Rate   Decimal       `gremlin:"rate"`
+		Payout Decimal       `gremlin:"payout"`
+		Total  *big.Int      `gremlin:"total"`
+		Count  int           `gremlin:"count"`
+		Grade  string        `gremlin:"grade"`
+		Wait   time.Duration `gremlin:"wait"`
+		Day    time.Time     `gremlin:"day"`
+	}
+	require.NoError(t, Decode(a, &v))
+	assert.Equal(t, "12.50", v.Rate.String())
+	assert.Equal(t, "0.1", v.Payout.String())
+	assert.Equal(t, "123456789012345678901234567890", v.Total.String())
+	assert.Equal(t, 3, v.Count)
+	assert.Equal(t, "A", v.Grade)
+	assert.Equal(t, time.Minute, v.Wait)
+	assert.Equal(t, time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC), v.Day)
+
+	n, err := Attribute{Type: TypeBigInteger, Value: big.NewInt(5)}.CoerceInt64()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	f, err := Attribute{Type: TypeInt16, Value: int16(-2)}.CoerceFloat64()
+	require.NoError(t, err)
+	assert.Equal(t, float64(-2), f)
+}
@@ -0,0 +1,110 @@
+package enrollment
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/northwesternmutual/grammes"
+	t "github.com/northwesternmutual/grammes/query/traversal"
+)
+
+// RateBucketSize is the width of hourly rate facet buckets
+const RateBucketSize = 10
+
+const (
+	careTypeFacet   = "care_type"
+	genderFacet     = "gender"
+	hourlyRateFacet = "hourly_rate"
+)
+
+// UnknownGender is the gender facet key of providers without gender
+const UnknownGender = "unknown"
+
+type RateBucketGRPCModel struct {
+	Min   float64
+	Max   float64
+	Count int64
+}
+
+type FacetsGRPCModel struct {
+	// CareTypes counts providers by matched service
+	CareTypes map[string]int64
+	// Genders counts distinct providers by gender, providers without gender are counted as UnknownGender
+	Genders map[string]int64
+	// HourlyRates counts distinct providers by min rate of matched services, ordered by Min.
+	// A provider is counted once in a bucket, and once in every bucket its matched services fall into.
+	HourlyRates []RateBucketGRPCModel
+}
+
+// BuildFacetQuery builds query counting providers found by BuildQuery for the same request.
+// Sort and pagination of the request are ignored.
+func BuildFacetQuery(req *GRPCModel) (t.String, Bindings, error) {
+	var err error
+	g := grammes.Traversal()
+	if req == nil {
+		return g, nil, errors.New("empty request")
+	}
+	b := make(Bindings)
+	g, err = providerServices(g, req, b)
+	if err != nil {
+		return g, nil, err
+	}
+	bucket := fmt.Sprintf("floor(_ / %d) * %d", RateBucketSize, RateBucketSize)
+	gender := t.NewTraversal().Coalesce(
+		t.NewTraversal().Values("gender").Raw(),
+		t.NewTraversal().Constant(strconv.Quote(UnknownGender)).Raw())
+	// every found row is provider -provides-> service pair, providers are deduplicated within rate buckets
+	// and before counting genders
+	return g.
+		GroupCount(careTypeFacet).By(t.NewTraversal().Select("e").Values("service").Raw()).
+		Group(hourlyRateFacet).
+		By(t.NewTraversal().Select("e").Values("min_rate").Math(bucket).Raw()).
+		By(t.NewTraversal().Dedup().Count().Raw()).
+		Dedup().
+		GroupCount(genderFacet).By(gender.Raw()).
+		Cap(careTypeFacet, genderFacet, hourlyRateFacet), b, nil
+}
+
+// BuildFacetResponse reads result of the query built by BuildFacetQuery
+func BuildFacetResponse(recs [][]byte) (*FacetsGRPCModel, error) {
+	rows, err := UnmarshalList(recs)
+	if err != nil {
+		return nil, err
+	}
+	res := &FacetsGRPCModel{
+		CareTypes: make(map[string]int64),
+		Genders:   make(map[string]int64),
+	}
+	if len(rows) == 0 {
+		return res, nil
+	}
+	if rows[0].Type != TypeMap {
+		return nil, fmt.Errorf("got %s where %s is expected", rows[0].Type, TypeMap)
+	}
+	facets := rows[0].MapValue()
+	for _, e := range facets.Get(careTypeFacet).MapValue().Entries() {
+		res.CareTypes[e.Key.ToString()] = e.Value.Int64Value()
+	}
+	for _, e := range facets.Get(genderFacet).MapValue().Entries() {
+		res.Genders[e.Key.ToString()] = e.Value.Int64Value()
+	}
+	for _, e := range facets.Get(hourlyRateFacet).MapValue().Entries() {
+		switch e.Key.Type {
+		case TypeInteger, TypeLong, TypeFloat, TypeDouble:
+		default:
+			return nil, fmt.Errorf("wrong hourly rate bucket: %s", e.Key.ToString())
+		}
+		min := numberValue(e.Key)
+		res.HourlyRates = append(res.HourlyRates, RateBucketGRPCModel{
+			Min:   min,
+			Max:   min + RateBucketSize,
+			Count: e.Value.Int64Value(),
+		})
+	}
+	sort.Slice(res.HourlyRates, func(i, j int) bool {
+		return res.HourlyRates[i].Min < res.HourlyRates[j].Min
+	})
+	return res, nil
+}
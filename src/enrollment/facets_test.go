@@ -0,0 +1,58 @@
+package enrollment
+
+import (
+	"testing"
+
+	"github.com/northwesternmutual/grammes"
+	t "github.com/northwesternmutual/grammes/query/traversal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BuildFacetQuery(te *testing.T) {
+	g := grammes.Traversal()
+	expected := g.V().Has("zip", "name", t.Custom("postalCode")).
+		In("lives").
+		HasLabel("provider").As("p").
+		OutE("provides").As("e").
+		And(t.NewTraversal().Has("max_rate", predicate("lte", "Double.valueOf(maxRate)")).Raw()).
+		InV().
+		Select("p").
+		GroupCount("care_type").By(t.NewTraversal().Select("e").Values("service").Raw()).
+		Group("hourly_rate").
+		By(t.NewTraversal().Select("e").Values("min_rate").Math("floor(_ / 10) * 10").Raw()).
+		By(t.NewTraversal().Dedup().Count().Raw()).
+		Dedup().
+		GroupCount("gender").By(t.Custom(`coalesce(values("gender"),constant("unknown"))`)).
+		Cap("care_type", "gender", "hourly_rate")
+
+	query, _, err := BuildFacetQuery(&GRPCModel{
+		PostalCode: "78704",
+		HourlyRate: &HourlyRateGRPCModel{Min: 0, Max: 50},
+		Sort:       &SortGRPCModel{Field: SortByAvgRank},
+		PageSize:   10,
+	})
+	assert.NoError(te, err)
+
+	assert.Equal(te, expected.String(), query.String())
+}
+
+func Test_BuildFacetResponse(te *testing.T) {
+	recs := [][]byte{
+		[]byte(`{"@type":"g:List","@value":[{"@type":"g:Map","@value":[` +
+			`"care_type",{"@type":"g:Map","@value":["childCare",{"@type":"g:Int64","@value":3},"petCare",{"@type":"g:Int64","@value":1}]},` +
+			`"gender",{"@type":"g:Map","@value":["female",{"@type":"g:Int64","@value":2},"male",{"@type":"g:Int64","@value":1},"unknown",{"@type":"g:Int64","@value":1}]},` +
+			`"hourly_rate",{"@type":"g:Map","@value":[{"@type":"g:Double","@value":20.0},{"@type":"g:Int64","@value":1},{"@type":"g:Double","@value":0.0},{"@type":"g:Int64","@value":3}]}` +
+			`]}]}`),
+	}
+
+	res, err := BuildFacetResponse(recs)
+	require.NoError(te, err)
+	assert.Equal(te, map[string]int64{"childCare": 3, "petCare": 1}, res.CareTypes)
+	// a provider without gender is counted as unknown
+	assert.Equal(te, map[string]int64{"female": 2, "male": 1, UnknownGender: 1}, res.Genders)
+	assert.Equal(te, []RateBucketGRPCModel{
+		{Min: 0, Max: 10, Count: 3},
+		{Min: 20, Max: 30, Count: 1},
+	}, res.HourlyRates)
+}
@@ -0,0 +1,1162 @@
+package enrollment
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// GraphBinaryMimeType is sent as a header of every GraphBinary request
+const GraphBinaryMimeType = "application/vnd.graphbinary-v1.0"
+
+const graphBinaryVersion = 0x81
+
+// GraphBinary 1.0 type codes
+const (
+	gbInt             byte = 0x01
+	gbLong            byte = 0x02
+	gbString          byte = 0x03
+	gbDate            byte = 0x04
+	gbTimestamp       byte = 0x05
+	gbClass           byte = 0x06
+	gbDouble          byte = 0x07
+	gbFloat           byte = 0x08
+	gbList            byte = 0x09
+	gbMap             byte = 0x0a
+	gbSet             byte = 0x0b
+	gbUUID            byte = 0x0c
+	gbEdge            byte = 0x0d
+	gbPath            byte = 0x0e
+	gbProperty        byte = 0x0f
+	gbVertex          byte = 0x11
+	gbVertexProperty  byte = 0x12
+	gbDirection       byte = 0x18
+	gbT               byte = 0x20
+	gbTraverser       byte = 0x21
+	gbBigDecimal      byte = 0x22
+	gbBigInteger      byte = 0x23
+	gbByte            byte = 0x24
+	gbByteBuffer      byte = 0x25
+	gbShort           byte = 0x26
+	gbBoolean         byte = 0x27
+	gbBulkSet         byte = 0x2a
+	gbTree            byte = 0x2b
+	gbChar            byte = 0x80
+	gbDuration        byte = 0x81
+	gbInstant         byte = 0x83
+	gbLocalDate       byte = 0x84
+	gbLocalDateTime   byte = 0x85
+	gbOffsetDateTime  byte = 0x88
+	gbUnspecifiedNull byte = 0xfe
+)
+
+const (
+	gbValueFlagNone byte = 0x00
+	gbValueFlagNull byte = 0x01
+)
+
+var gbTypeCodes = map[DBType]byte{
+	TypeInteger:        gbInt,
+	TypeLong:           gbLong,
+	TypeString:         gbString,
+	TypeDate:           gbDate,
+	TypeTimestamp:      gbTimestamp,
+	TypeClass:          gbClass,
+	TypeDouble:         gbDouble,
+	TypeFloat:          gbFloat,
+	TypeList:           gbList,
+	TypeMap:            gbMap,
+	TypeSet:            gbSet,
+	TypeUUID:           gbUUID,
+	TypeEdge:           gbEdge,
+	TypePath:           gbPath,
+	TypeProperty:       gbProperty,
+	TypeTree:           gbTree,
+	TypeVertex:         gbVertex,
+	TypeVertexProperty: gbVertexProperty,
+	TypeDirection:      gbDirection,
+	TypeT:              gbT,
+	TypeTraverser:      gbTraverser,
+	TypeBoolean:        gbBoolean,
+	TypeBulkSet:        gbBulkSet,
+	TypeBigDecimal:     gbBigDecimal,
+	TypeBigInteger:     gbBigInteger,
+	TypeByte:           gbByte,
+	TypeByteBuffer:     gbByteBuffer,
+	TypeInt16:          gbShort,
+	TypeChar:           gbChar,
+	TypeDuration:       gbDuration,
+	TypeInstant:        gbInstant,
+	TypeLocalDate:      gbLocalDate,
+	TypeLocalDateTime:  gbLocalDateTime,
+	TypeOffsetDateTime: gbOffsetDateTime,
+}
+
+var gbDBTypes = func() map[byte]DBType {
+	types := make(map[byte]DBType, len(gbTypeCodes))
+	for t, code := range gbTypeCodes {
+		types[code] = t
+	}
+	return types
+}()
+
+// MarshalGraphBinary writes the attribute as a fully qualified GraphBinary 1.0 value
+func MarshalGraphBinary(a Attribute) ([]byte, error) {
+	var w gbWriter
+	if err := w.writeValue(a); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalGraphBinary reads a fully qualified GraphBinary 1.0 value
+func UnmarshalGraphBinary(b []byte) (Attribute, error) {
+	r := gbReader{r: bytes.NewReader(b)}
+	a, err := r.readValue()
+	if err != nil {
+		return Attribute{}, err
+	}
+	if r.r.Len() > 0 {
+		return Attribute{}, fmt.Errorf("%d bytes left after GraphBinary value", r.r.Len())
+	}
+	return a, nil
+}
+
+type gbWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *gbWriter) writeInt(v int32) {
+	_ = binary.Write(&w.buf, binary.BigEndian, v)
+}
+
+func (w *gbWriter) writeLong(v int64) {
+	_ = binary.Write(&w.buf, binary.BigEndian, v)
+}
+
+func (w *gbWriter) writeString(s string) {
+	w.writeInt(int32(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (w *gbWriter) writeUUID(s string) error {
+	b, err := hex.DecodeString(strings.Replace(s, "-", "", -1))
+	if err != nil || len(b) != 16 {
+		return fmt.Errorf("wrong %s value: %s", TypeUUID, s)
+	}
+	w.buf.Write(b)
+	return nil
+}
+
+func (w *gbWriter) writeNull() {
+	w.buf.WriteByte(gbUnspecifiedNull)
+	w.buf.WriteByte(gbValueFlagNull)
+}
+
+// writeValue writes {type_code}{value_flag}{value}
+func (w *gbWriter) writeValue(a Attribute) error {
+	if a.Type == "" {
+		w.writeNull()
+		return nil
+	}
+	code, ok := gbTypeCodes[a.Type]
+	if !ok {
+		return fmt.Errorf("%s is not supported by GraphBinary", a.Type)
+	}
+	w.buf.WriteByte(code)
+	w.buf.WriteByte(gbValueFlagNone)
+	return w.writeBareValue(a)
+}
+
+func (w *gbWriter) writeBareValue(a Attribute) error {
+	switch a.Type {
+	case TypeInteger:
+		n, ok := integerValue(a)
+		if !ok {
+			return attributeValueError(a)
+		}
+		w.writeInt(int32(n))
+	case TypeLong:
+		n, ok := integerValue(a)
+		if !ok {
+			return attributeValueError(a)
+		}
+		w.writeLong(n)
+	case TypeString, TypeClass:
+		s, ok := a.Value.(string)
+		if !ok {
+			return attributeValueError(a)
+		}
+		w.writeString(s)
+	case TypeDate, TypeTimestamp:
+		tm, ok := a.Value.(time.Time)
+		if !ok {
+			return attributeValueError(a)
+		}
+		w.writeLong(unixMillis(tm))
+	case TypeDouble:
+		f, ok := floatValue(a)
+		if !ok {
+			return attributeValueError(a)
+		}
+		_ = binary.Write(&w.buf, binary.BigEndian, f)
+	case TypeFloat:
+		f, ok := floatValue(a)
+		if !ok {
+			return attributeValueError(a)
+		}
+		_ = binary.Write(&w.buf, binary.BigEndian, float32(f))
+	case TypeBoolean:
+		v, ok := a.Value.(bool)
+		if !ok {
+			return attributeValueError(a)
+		}
+		if v {
+			w.buf.WriteByte(1)
+		} else {
+			w.buf.WriteByte(0)
+		}
+	case TypeUUID:
+		return w.writeUUID(a.UUIDValue())
+	case TypeT:
+		w.writeEnum(string(a.TValue()))
+	case TypeDirection:
+		w.writeEnum(string(a.DirectionValue()))
+	case TypeList, TypeSet:
+		return w.writeList(a.ListValue())
+	case TypeMap:
+		return w.writeMap(a.MapValue())
+	case TypeProperty:
+		return w.writeProperty(a.PropertyValue())
+	case TypeVertexProperty:
+		return w.writeVertexProperty(a.VertexPropertyValue())
+	case TypeVertex:
+		return w.writeVertex(a.VertexValue())
+	case TypeEdge:
+		return w.writeEdge(a.EdgeValue())
+	case TypePath:
+		return w.writePath(a.PathValue())
+	case TypeTree:
+		return w.writeTree(a.TreeValue())
+	case TypeTraverser:
+		tr := a.TraverserValue()
+		w.writeLong(tr.Bulk)
+		return w.writeValue(tr.Value)
+	case TypeBulkSet:
+		s := a.BulkSetValue()
+		w.writeInt(int32(len(s)))
+		for _, item := range s {
+			if err := w.writeValue(item.Value); err != nil {
+				return err
+			}
+			w.writeLong(item.Bulk)
+		}
+	case TypeBigDecimal:
+		d, ok := a.Value.(Decimal)
+		if !ok {
+			return attributeValueError(a)
+		}
+		w.writeInt(d.Scale)
+		w.writeBigInt(d.unscaled())
+	case TypeBigInteger:
+		n, ok := a.Value.(*big.Int)
+		if !ok || n == nil {
+			return attributeValueError(a)
+		}
+		w.writeBigInt(n)
+	case TypeByte:
+		n, ok := integerValue(a)
+		if !ok || n < math.MinInt8 || n > math.MaxInt8 {
+			return attributeValueError(a)
+		}
+		w.buf.WriteByte(byte(int8(n)))
+	case TypeInt16:
+		n, ok := integerValue(a)
+		if !ok || n < math.MinInt16 || n > math.MaxInt16 {
+			return attributeValueError(a)
+		}
+		_ = binary.Write(&w.buf, binary.BigEndian, int16(n))
+	case TypeByteBuffer:
+		b, ok := a.Value.([]byte)
+		if !ok {
+			return attributeValueError(a)
+		}
+		w.writeInt(int32(len(b)))
+		w.buf.Write(b)
+	case TypeChar:
+		r, ok := a.Value.(rune)
+		if !ok || !utf8.ValidRune(r) {
+			return attributeValueError(a)
+		}
+		w.buf.WriteRune(r)
+	case TypeDuration:
+		d, ok := a.Value.(time.Duration)
+		if !ok {
+			return attributeValueError(a)
+		}
+		// nanos are never negative as in java.time.Duration
+		secs, nanos := int64(d/time.Second), int32(d%time.Second)
+		if nanos < 0 {
+			secs, nanos = secs-1, nanos+int32(time.Second)
+		}
+		w.writeLong(secs)
+		w.writeInt(nanos)
+	case TypeInstant, TypeLocalDate, TypeLocalDateTime, TypeOffsetDateTime:
+		tm, ok := a.Value.(time.Time)
+		if !ok {
+			return attributeValueError(a)
+		}
+		w.writeJavaTime(a.Type, tm)
+	default:
+		return fmt.Errorf("%s is not supported by GraphBinary", a.Type)
+	}
+	return nil
+}
+
+// writeBigInt writes {length}{two's complement big endian bytes} as java.math.BigInteger.toByteArray
+func (w *gbWriter) writeBigInt(n *big.Int) {
+	var b []byte
+	if n.Sign() >= 0 {
+		b = n.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+	} else {
+		// -n-1 with inverted bits is n in two's complement
+		b = new(big.Int).Sub(new(big.Int).Neg(n), big.NewInt(1)).Bytes()
+		for i := range b {
+			b[i] = ^b[i]
+		}
+		if len(b) == 0 || b[0]&0x80 == 0 {
+			b = append([]byte{0xff}, b...)
+		}
+	}
+	w.writeInt(int32(len(b)))
+	w.buf.Write(b)
+}
+
+// writeJavaTime writes Instant as {seconds}{nanos}, LocalDate as {year}{month}{day},
+// LocalDateTime as {LocalDate}{nanos of day} and OffsetDateTime as {LocalDateTime}{offset seconds}
+func (w *gbWriter) writeJavaTime(t DBType, tm time.Time) {
+	if t == TypeInstant {
+		w.writeLong(tm.Unix())
+		w.writeInt(int32(tm.Nanosecond()))
+		return
+	}
+	w.writeInt(int32(tm.Year()))
+	w.buf.WriteByte(byte(tm.Month()))
+	w.buf.WriteByte(byte(tm.Day()))
+	if t == TypeLocalDate {
+		return
+	}
+	h, m, sec := tm.Clock()
+	w.writeLong(int64(h)*int64(time.Hour) + int64(m)*int64(time.Minute) + int64(sec)*int64(time.Second) + int64(tm.Nanosecond()))
+	if t == TypeOffsetDateTime {
+		_, offset := tm.Zone()
+		w.writeInt(int32(offset))
+	}
+}
+
+// writeEnum writes an enum value, which is a fully qualified string
+func (w *gbWriter) writeEnum(s string) {
+	w.buf.WriteByte(gbString)
+	w.buf.WriteByte(gbValueFlagNone)
+	w.writeString(s)
+}
+
+func (w *gbWriter) writeList(items List) error {
+	w.writeInt(int32(len(items)))
+	for _, item := range items {
+		if err := w.writeValue(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *gbWriter) writeMap(m Map) error {
+	w.writeInt(int32(m.Len()))
+	for _, e := range m.Entries() {
+		if err := w.writeValue(e.Key); err != nil {
+			return err
+		}
+		if err := w.writeValue(e.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *gbWriter) writeProperty(p Property) error {
+	w.writeString(p.Key)
+	if err := w.writeValue(p.Value); err != nil {
+		return err
+	}
+	// parent element
+	w.writeNull()
+	return nil
+}
+
+func (w *gbWriter) writeVertexProperty(p VertexProperty) error {
+	if err := w.writeValue(p.ID); err != nil {
+		return err
+	}
+	w.writeString(p.Label)
+	if err := w.writeValue(p.Value); err != nil {
+		return err
+	}
+	// parent vertex and meta properties
+	w.writeNull()
+	w.writeNull()
+	return nil
+}
+
+func (w *gbWriter) writeVertex(v Vertex) error {
+	if err := w.writeValue(v.ID); err != nil {
+		return err
+	}
+	w.writeString(v.Label)
+	if len(v.Properties) == 0 {
+		w.writeNull()
+		return nil
+	}
+	keys := make([]string, 0, len(v.Properties))
+	for k := range v.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var props List
+	for _, k := range keys {
+		for _, p := range v.Properties[k] {
+			props = append(props, Attribute{Type: TypeVertexProperty, Value: p})
+		}
+	}
+	return w.writeValue(Attribute{Type: TypeList, Value: props})
+}
+
+func (w *gbWriter) writeEdge(e Edge) error {
+	if err := w.writeValue(e.ID); err != nil {
+		return err
+	}
+	w.writeString(e.Label)
+	if err := w.writeValue(e.InV); err != nil {
+		return err
+	}
+	w.writeString(e.InVLabel)
+	if err := w.writeValue(e.OutV); err != nil {
+		return err
+	}
+	w.writeString(e.OutVLabel)
+	// parent graph
+	w.writeNull()
+	if len(e.Properties) == 0 {
+		w.writeNull()
+		return nil
+	}
+	keys := make([]string, 0, len(e.Properties))
+	for k := range e.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	props := make(List, 0, len(keys))
+	for _, k := range keys {
+		props = append(props, Attribute{Type: TypeProperty, Value: e.Properties[k]})
+	}
+	return w.writeValue(Attribute{Type: TypeList, Value: props})
+}
+
+func (w *gbWriter) writePath(p Path) error {
+	labels := make(List, 0, len(p.Labels))
+	for _, names := range p.Labels {
+		set := make(List, 0, len(names))
+		for _, name := range names {
+			set = append(set, Attribute{Type: TypeString, Value: name})
+		}
+		labels = append(labels, Attribute{Type: TypeSet, Value: set})
+	}
+	if err := w.writeValue(Attribute{Type: TypeList, Value: labels}); err != nil {
+		return err
+	}
+	return w.writeValue(Attribute{Type: TypeList, Value: p.Objects})
+}
+
+// writeTree writes {length}{key}{children}..., children is a bare tree
+func (w *gbWriter) writeTree(tr Tree) error {
+	w.writeInt(int32(len(tr)))
+	for _, n := range tr {
+		if err := w.writeValue(n.Key); err != nil {
+			return err
+		}
+		if err := w.writeTree(n.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type gbReader struct {
+	r *bytes.Reader
+}
+
+var errGraphBinaryLength = errors.New("wrong GraphBinary length")
+
+func (r *gbReader) readByte() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err == io.EOF {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return b, err
+}
+
+func (r *gbReader) read(v interface{}) error {
+	err := binary.Read(r.r, binary.BigEndian, v)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func (r *gbReader) readInt() (int32, error) {
+	var v int32
+	err := r.read(&v)
+	return v, err
+}
+
+func (r *gbReader) readLong() (int64, error) {
+	var v int64
+	err := r.read(&v)
+	return v, err
+}
+
+// readLength reads a length and checks it can be read from what is left
+func (r *gbReader) readLength() (int, error) {
+	n, err := r.readInt()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 || int(n) > r.r.Len() {
+		return 0, errGraphBinaryLength
+	}
+	return int(n), nil
+}
+
+func (r *gbReader) readString() (string, error) {
+	n, err := r.readLength()
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (r *gbReader) readUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return "", io.ErrUnexpectedEOF
+	}
+	s := hex.EncodeToString(b)
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:], nil
+}
+
+// readValue reads {type_code}{value_flag}{value}, a null value is read as empty Attribute
+func (r *gbReader) readValue() (Attribute, error) {
+	code, err := r.readByte()
+	if err != nil {
+		return Attribute{}, err
+	}
+	flag, err := r.readByte()
+	if err != nil {
+		return Attribute{}, err
+	}
+	if flag == gbValueFlagNull {
+		return Attribute{}, nil
+	}
+	if flag != gbValueFlagNone {
+		return Attribute{}, fmt.Errorf("wrong GraphBinary value flag: 0x%02x", flag)
+	}
+	t, ok := gbDBTypes[code]
+	if !ok {
+		return Attribute{}, fmt.Errorf("unsupported GraphBinary type code: 0x%02x", code)
+	}
+	return r.readBareValue(t)
+}
+
+func (r *gbReader) readBareValue(t DBType) (Attribute, error) {
+	a := Attribute{Type: t}
+	var err error
+	switch t {
+	case TypeInteger:
+		a.Value, err = r.readInt()
+	case TypeLong:
+		a.Value, err = r.readLong()
+	case TypeString, TypeClass:
+		a.Value, err = r.readString()
+	case TypeDate, TypeTimestamp:
+		var ms int64
+		ms, err = r.readLong()
+		a.Value = time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond)).UTC()
+	case TypeDouble:
+		var f float64
+		err = r.read(&f)
+		a.Value = f
+	case TypeFloat:
+		var f float32
+		err = r.read(&f)
+		a.Value = float64(f)
+	case TypeBoolean:
+		var b byte
+		b, err = r.readByte()
+		a.Value = b != 0
+	case TypeUUID:
+		a.Value, err = r.readUUID()
+	case TypeT:
+		var s string
+		s, err = r.readEnum()
+		a.Value = T(s)
+	case TypeDirection:
+		var s string
+		s, err = r.readEnum()
+		a.Value = Direction(s)
+	case TypeList, TypeSet:
+		a.Value, err = r.readList()
+	case TypeMap:
+		a.Value, err = r.readMap()
+	case TypeProperty:
+		a.Value, err = r.readProperty()
+	case TypeVertexProperty:
+		a.Value, err = r.readVertexProperty()
+	case TypeVertex:
+		a.Value, err = r.readVertex()
+	case TypeEdge:
+		a.Value, err = r.readEdge()
+	case TypePath:
+		a.Value, err = r.readPath()
+	case TypeTree:
+		a.Value, err = r.readTree()
+	case TypeTraverser:
+		a.Value, err = r.readTraverser()
+	case TypeBulkSet:
+		a.Value, err = r.readBulkSet()
+	case TypeBigDecimal:
+		var d Decimal
+		if d.Scale, err = r.readInt(); err == nil {
+			d.Unscaled, err = r.readBigInt()
+		}
+		a.Value = d
+	case TypeBigInteger:
+		a.Value, err = r.readBigInt()
+	case TypeByte:
+		var b byte
+		b, err = r.readByte()
+		a.Value = int8(b)
+	case TypeInt16:
+		var n int16
+		err = r.read(&n)
+		a.Value = n
+	case TypeByteBuffer:
+		a.Value, err = r.readBytes()
+	case TypeChar:
+		a.Value, err = r.readChar()
+	case TypeDuration:
+		a.Value, err = r.readDuration()
+	case TypeInstant, TypeLocalDate, TypeLocalDateTime, TypeOffsetDateTime:
+		a.Value, err = r.readJavaTime(t)
+	default:
+		err = fmt.Errorf("%s is not supported by GraphBinary", t)
+	}
+	if err != nil {
+		return Attribute{}, err
+	}
+	return a, nil
+}
+
+func (r *gbReader) readBytes() ([]byte, error) {
+	n, err := r.readLength()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// readBigInt reads {length}{two's complement big endian bytes}
+func (r *gbReader) readBigInt() (*big.Int, error) {
+	b, err := r.readBytes()
+	if err != nil {
+		return nil, err
+	}
+	n := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8))
+	}
+	return n, nil
+}
+
+// readChar reads one utf-8 encoded character, its length is told by the first byte
+func (r *gbReader) readChar() (rune, error) {
+	first, err := r.readByte()
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	switch {
+	case first < 0x80:
+		return rune(first), nil
+	case first&0xe0 == 0xc0:
+		n = 2
+	case first&0xf0 == 0xe0:
+		n = 3
+	case first&0xf8 == 0xf0:
+		n = 4
+	default:
+		return 0, fmt.Errorf("wrong %s value", TypeChar)
+	}
+	b := make([]byte, n)
+	b[0] = first
+	if _, err := io.ReadFull(r.r, b[1:]); err != nil {
+		return 0, io.ErrUnexpectedEOF
+	}
+	c, size := utf8.DecodeRune(b)
+	if c == utf8.RuneError || size != n {
+		return 0, fmt.Errorf("wrong %s value", TypeChar)
+	}
+	return c, nil
+}
+
+func (r *gbReader) readDuration() (time.Duration, error) {
+	secs, err := r.readLong()
+	if err != nil {
+		return 0, err
+	}
+	nanos, err := r.readInt()
+	if err != nil {
+		return 0, err
+	}
+	d, err := addDuration(0, secs, time.Second)
+	if err != nil {
+		return 0, err
+	}
+	return addDuration(d, int64(nanos), time.Nanosecond)
+}
+
+func (r *gbReader) readJavaTime(t DBType) (time.Time, error) {
+	if t == TypeInstant {
+		secs, err := r.readLong()
+		if err != nil {
+			return time.Time{}, err
+		}
+		nanos, err := r.readInt()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(secs, int64(nanos)).UTC(), nil
+	}
+	var date struct {
+		Year  int32
+		Month byte
+		Day   byte
+	}
+	if err := r.read(&date); err != nil {
+		return time.Time{}, err
+	}
+	tm := time.Date(int(date.Year), time.Month(date.Month), int(date.Day), 0, 0, 0, 0, time.UTC)
+	if t == TypeLocalDate {
+		return tm, nil
+	}
+	nanos, err := r.readLong()
+	if err != nil {
+		return time.Time{}, err
+	}
+	tm = tm.Add(time.Duration(nanos))
+	if t == TypeLocalDateTime {
+		return tm, nil
+	}
+	offset, err := r.readInt()
+	if err != nil {
+		return time.Time{}, err
+	}
+	// tm is the local time at the offset
+	return fixedZone(tm.Add(-time.Duration(offset) * time.Second).In(time.FixedZone("", int(offset)))), nil
+}
+
+func (r *gbReader) readEnum() (string, error) {
+	a, err := r.readValue()
+	if err != nil {
+		return "", err
+	}
+	if a.Type != TypeString {
+		return "", fmt.Errorf("got %s where enum %s is expected", a.Type, TypeString)
+	}
+	return a.StringValue(), nil
+}
+
+func (r *gbReader) readList() (List, error) {
+	n, err := r.readLength()
+	if err != nil {
+		return nil, err
+	}
+	items := make(List, 0, n)
+	for i := 0; i < n; i++ {
+		a, err := r.readValue()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, a)
+	}
+	return items, nil
+}
+
+func (r *gbReader) readMap() (Map, error) {
+	n, err := r.readLength()
+	if err != nil {
+		return Map{}, err
+	}
+	m := Map{}
+	for i := 0; i < n; i++ {
+		key, err := r.readValue()
+		if err != nil {
+			return Map{}, err
+		}
+		value, err := r.readValue()
+		if err != nil {
+			return Map{}, err
+		}
+		m.add(key, value)
+	}
+	return m, nil
+}
+
+func (r *gbReader) readProperty() (Property, error) {
+	var p Property
+	var err error
+	if p.Key, err = r.readString(); err != nil {
+		return Property{}, err
+	}
+	if p.Value, err = r.readValue(); err != nil {
+		return Property{}, err
+	}
+	// parent element
+	if _, err = r.readValue(); err != nil {
+		return Property{}, err
+	}
+	return p, nil
+}
+
+func (r *gbReader) readVertexProperty() (VertexProperty, error) {
+	var p VertexProperty
+	var err error
+	if p.ID, err = r.readValue(); err != nil {
+		return VertexProperty{}, err
+	}
+	if p.Label, err = r.readString(); err != nil {
+		return VertexProperty{}, err
+	}
+	if p.Value, err = r.readValue(); err != nil {
+		return VertexProperty{}, err
+	}
+	// parent vertex and meta properties
+	for i := 0; i < 2; i++ {
+		if _, err = r.readValue(); err != nil {
+			return VertexProperty{}, err
+		}
+	}
+	return p, nil
+}
+
+func (r *gbReader) readVertex() (Vertex, error) {
+	var v Vertex
+	var err error
+	if v.ID, err = r.readValue(); err != nil {
+		return Vertex{}, err
+	}
+	if v.Label, err = r.readString(); err != nil {
+		return Vertex{}, err
+	}
+	props, err := r.readValue()
+	if err != nil {
+		return Vertex{}, err
+	}
+	for _, p := range props.ListValue() {
+		if p.Type != TypeVertexProperty {
+			return Vertex{}, fmt.Errorf("got %s where %s is expected", p.Type, TypeVertexProperty)
+		}
+		if v.Properties == nil {
+			v.Properties = make(map[string][]VertexProperty)
+		}
+		vp := p.VertexPropertyValue()
+		v.Properties[vp.Label] = append(v.Properties[vp.Label], vp)
+	}
+	return v, nil
+}
+
+func (r *gbReader) readEdge() (Edge, error) {
+	var e Edge
+	var err error
+	if e.ID, err = r.readValue(); err != nil {
+		return Edge{}, err
+	}
+	if e.Label, err = r.readString(); err != nil {
+		return Edge{}, err
+	}
+	if e.InV, err = r.readValue(); err != nil {
+		return Edge{}, err
+	}
+	if e.InVLabel, err = r.readString(); err != nil {
+		return Edge{}, err
+	}
+	if e.OutV, err = r.readValue(); err != nil {
+		return Edge{}, err
+	}
+	if e.OutVLabel, err = r.readString(); err != nil {
+		return Edge{}, err
+	}
+	// parent graph
+	if _, err = r.readValue(); err != nil {
+		return Edge{}, err
+	}
+	props, err := r.readValue()
+	if err != nil {
+		return Edge{}, err
+	}
+	for _, p := range props.ListValue() {
+		if p.Type != TypeProperty {
+			return Edge{}, fmt.Errorf("got %s where %s is expected", p.Type, TypeProperty)
+		}
+		if e.Properties == nil {
+			e.Properties = make(map[string]Property)
+		}
+		prop := p.PropertyValue()
+		e.Properties[prop.Key] = prop
+	}
+	return e, nil
+}
+
+func (r *gbReader) readPath() (Path, error) {
+	labels, err := r.readValue()
+	if err != nil {
+		return Path{}, err
+	}
+	objects, err := r.readValue()
+	if err != nil {
+		return Path{}, err
+	}
+	p := Path{Objects: objects.ListValue()}
+	for _, set := range labels.ListValue() {
+		var names []string
+		for _, name := range set.ListValue() {
+			names = append(names, name.ToString())
+		}
+		p.Labels = append(p.Labels, names)
+	}
+	return p, nil
+}
+
+func (r *gbReader) readTree() (Tree, error) {
+	n, err := r.readLength()
+	if err != nil {
+		return nil, err
+	}
+	var tr Tree
+	for i := 0; i < n; i++ {
+		key, err := r.readValue()
+		if err != nil {
+			return nil, err
+		}
+		children, err := r.readTree()
+		if err != nil {
+			return nil, err
+		}
+		tr = append(tr, TreeNode{Key: key, Children: children})
+	}
+	return tr, nil
+}
+
+func (r *gbReader) readTraverser() (Traverser, error) {
+	bulk, err := r.readLong()
+	if err != nil {
+		return Traverser{}, err
+	}
+	value, err := r.readValue()
+	if err != nil {
+		return Traverser{}, err
+	}
+	return Traverser{Bulk: bulk, Value: value}, nil
+}
+
+func (r *gbReader) readBulkSet() (BulkSet, error) {
+	n, err := r.readLength()
+	if err != nil {
+		return nil, err
+	}
+	var s BulkSet
+	for i := 0; i < n; i++ {
+		value, err := r.readValue()
+		if err != nil {
+			return nil, err
+		}
+		bulk, err := r.readLong()
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, BulkSetItem{Value: value, Bulk: bulk})
+	}
+	return s, nil
+}
+
+// GraphBinaryRequest is a request message, Args usually are gremlin, language and bindings
+type GraphBinaryRequest struct {
+	RequestID string
+	Op        string
+	Processor string
+	Args      Map
+}
+
+// GraphBinaryResponse is a response message, Data is the result of the request
+type GraphBinaryResponse struct {
+	RequestID        string
+	StatusCode       int32
+	StatusMessage    string
+	StatusAttributes Map
+	ResultMeta       Map
+	Data             Attribute
+}
+
+// MarshalGraphBinaryRequest writes the request with the mime type header, as it is sent to the server
+func MarshalGraphBinaryRequest(req *GraphBinaryRequest) ([]byte, error) {
+	var w gbWriter
+	w.buf.WriteByte(byte(len(GraphBinaryMimeType)))
+	w.buf.WriteString(GraphBinaryMimeType)
+	w.buf.WriteByte(graphBinaryVersion)
+	if err := w.writeUUID(req.RequestID); err != nil {
+		return nil, err
+	}
+	w.writeString(req.Op)
+	w.writeString(req.Processor)
+	if err := w.writeMap(req.Args); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalGraphBinaryRequest reads the request written by MarshalGraphBinaryRequest
+func UnmarshalGraphBinaryRequest(b []byte) (*GraphBinaryRequest, error) {
+	if len(b) == 0 || len(b) < int(b[0])+1 || string(b[1:b[0]+1]) != GraphBinaryMimeType {
+		return nil, errors.New("GraphBinary mime type header is missing")
+	}
+	r := gbReader{r: bytes.NewReader(b[b[0]+1:])}
+	if err := r.readVersion(); err != nil {
+		return nil, err
+	}
+	var req GraphBinaryRequest
+	var err error
+	if req.RequestID, err = r.readUUID(); err != nil {
+		return nil, err
+	}
+	if req.Op, err = r.readString(); err != nil {
+		return nil, err
+	}
+	if req.Processor, err = r.readString(); err != nil {
+		return nil, err
+	}
+	if req.Args, err = r.readMap(); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// MarshalGraphBinaryResponse writes the response as the server sends it
+func MarshalGraphBinaryResponse(resp *GraphBinaryResponse) ([]byte, error) {
+	var w gbWriter
+	w.buf.WriteByte(graphBinaryVersion)
+	if len(resp.RequestID) == 0 {
+		w.buf.WriteByte(gbValueFlagNull)
+	} else {
+		w.buf.WriteByte(gbValueFlagNone)
+		if err := w.writeUUID(resp.RequestID); err != nil {
+			return nil, err
+		}
+	}
+	w.writeInt(resp.StatusCode)
+	if len(resp.StatusMessage) == 0 {
+		w.buf.WriteByte(gbValueFlagNull)
+	} else {
+		w.buf.WriteByte(gbValueFlagNone)
+		w.writeString(resp.StatusMessage)
+	}
+	if err := w.writeMap(resp.StatusAttributes); err != nil {
+		return nil, err
+	}
+	if err := w.writeMap(resp.ResultMeta); err != nil {
+		return nil, err
+	}
+	if err := w.writeValue(resp.Data); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalGraphBinaryResponse reads a response message of the server
+func UnmarshalGraphBinaryResponse(b []byte) (*GraphBinaryResponse, error) {
+	r := gbReader{r: bytes.NewReader(b)}
+	if err := r.readVersion(); err != nil {
+		return nil, err
+	}
+	var resp GraphBinaryResponse
+	var err error
+	flag, err := r.readByte()
+	if err != nil {
+		return nil, err
+	}
+	if flag == gbValueFlagNone {
+		if resp.RequestID, err = r.readUUID(); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode, err = r.readInt(); err != nil {
+		return nil, err
+	}
+	if flag, err = r.readByte(); err != nil {
+		return nil, err
+	}
+	if flag == gbValueFlagNone {
+		if resp.StatusMessage, err = r.readString(); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusAttributes, err = r.readMap(); err != nil {
+		return nil, err
+	}
+	if resp.ResultMeta, err = r.readMap(); err != nil {
+		return nil, err
+	}
+	if resp.Data, err = r.readValue(); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (r *gbReader) readVersion() error {
+	v, err := r.readByte()
+	if err != nil {
+		return err
+	}
+	if v != graphBinaryVersion {
+		return fmt.Errorf("unsupported GraphBinary version: 0x%02x", v)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package enrollment
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalGraphBinary_Bytes(t *testing.T) {
+	tests := []struct {
+		a   Attribute
+		bin []byte
+	}{
+		{Attribute{Type: TypeInteger, Value: int32(1)}, []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x01}},
+		{Attribute{Type: TypeLong, Value: int64(-1)}, []byte{0x02, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{Attribute{Type: TypeString, Value: "ab"}, []byte{0x03, 0x00, 0x00, 0x00, 0x00, 0x02, 'a', 'b'}},
+		{Attribute{Type: TypeBoolean, Value: true}, []byte{0x27, 0x00, 0x01}},
+		{Attribute{Type: TypeT, Value: TID}, []byte{0x20, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x02, 'i', 'd'}},
+		{Attribute{}, []byte{0xfe, 0x01}},
+		{Attribute{Type: TypeDate, Value: time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)},
+			[]byte{0x04, 0x00, 0x00, 0x00, 0xe6, 0x77, 0xd2, 0x1f, 0xd8, 0x18}},
+		{Attribute{Type: TypeTimestamp, Value: time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			[]byte{0x05, 0x00, 0xff, 0xff, 0xc7, 0x7c, 0xed, 0xd3, 0x28, 0x00}},
+		{Attribute{Type: TypeList, Value: List{{Type: TypeInteger, Value: int32(2)}}},
+			[]byte{0x09, 0x00, 0x00, 0x00, 0x00, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x02}},
+	}
+	for _, test := range tests {
+		b, err := MarshalGraphBinary(test.a)
+		require.NoError(t, err)
+		assert.Equal(t, test.bin, b, test.a.Type)
+
+		a, err := UnmarshalGraphBinary(test.bin)
+		require.NoError(t, err)
+		assert.Equal(t, test.a, a)
+	}
+}
+
+// tree 78704 -> s1 as it is written by GraphBinary 1.0 servers: tree items are fully qualified keys and bare child trees
+var graphBinaryTree = []byte{
+	0x2b, 0x00, 0x00, 0x00, 0x00, 0x01,
+	0x03, 0x00, 0x00, 0x00, 0x00, 0x05, '7', '8', '7', '0', '4',
+	0x00, 0x00, 0x00, 0x01,
+	0x03, 0x00, 0x00, 0x00, 0x00, 0x02, 's', '1',
+	0x00, 0x00, 0x00, 0x00,
+}
+
+func TestGraphBinary_Tree(t *testing.T) {
+	tree := Attribute{Type: TypeTree, Value: Tree{{
+		Key:      Attribute{Type: TypeString, Value: "78704"},
+		Children: Tree{{Key: Attribute{Type: TypeString, Value: "s1"}}},
+	}}}
+	b, err := MarshalGraphBinary(tree)
+	require.NoError(t, err)
+	assert.Equal(t, graphBinaryTree, b)
+
+	a, err := UnmarshalGraphBinary(graphBinaryTree)
+	require.NoError(t, err)
+	require.Equal(t, TypeTree, a.Type)
+	tr := a.TreeValue()
+	require.Len(t, tr, 1)
+	assert.Equal(t, "78704", tr[0].Key.StringValue())
+	require.Len(t, tr[0].Children, 1)
+	assert.Equal(t, "s1", tr[0].Children[0].Key.StringValue())
+	assert.Empty(t, tr[0].Children[0].Children)
+
+	// 0x10 is Graph, which is not supported
+	_, err = UnmarshalGraphBinary(append([]byte{0x10}, graphBinaryTree[1:]...))
+	assert.Error(t, err)
+}
+
+func TestMarshalGraphBinary_RoundTrip(t *testing.T) {
+	js := `{"@type":"g:List","@value":[` +
+		`{"@type":"g:Map","@value":[{"@type":"g:T","@value":"id"},{"@type":"g:Int64","@value":1},"sitter_id","s1",` +
+		`{"@type":"g:Int32","@value":7},{"@type":"g:Set","@value":["childCare"]}]},` +
+		`{"@type":"g:Double","@value":1.25},{"@type":"g:Float","@value":4.5},false,null,` +
+		`{"@type":"g:Date","@value":1600000000000},{"@type":"g:Timestamp","@value":1600000000001},` +
+		`{"@type":"g:UUID","@value":"41d2e28a-20a4-4ab0-b379-d810dede3786"},{"@type":"g:Class","@value":"java.lang.String"},` +
+		`{"@type":"g:Direction","@value":"OUT"},` +
+		`{"@type":"g:Property","@value":{"key":"min_rate","value":{"@type":"g:Int32","@value":10}}},` +
+		`{"@type":"g:Vertex","@value":{"id":{"@type":"g:Int64","@value":1},"label":"provider","properties":{` +
+		`"sitter_id":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":10},"value":"s1","label":"sitter_id"}}]}}},` +
+		`{"@type":"g:Edge","@value":{"id":{"@type":"g:Int64","@value":7},"label":"provides","inVLabel":"service","outVLabel":"provider",` +
+		`"inV":{"@type":"g:Int64","@value":2},"outV":{"@type":"g:Int64","@value":1},` +
+		`"properties":{"min_rate":{"@type":"g:Property","@value":{"key":"min_rate","value":{"@type":"g:Double","@value":12.5}}}}}},` +
+		`{"@type":"g:Path","@value":{"labels":{"@type":"g:List","@value":[{"@type":"g:Set","@value":["p"]}]},"objects":{"@type":"g:List","@value":["s1"]}}},` +
+		`{"@type":"g:BulkSet","@value":["s1",{"@type":"g:Int64","@value":2}]},` +
+		`{"@type":"g:Tree","@value":[{"key":"78704","value":{"@type":"g:Tree","@value":[{"key":"s1","value":{"@type":"g:Tree","@value":[]}}]}}]},` +
+		`{"@type":"g:Traverser","@value":{"bulk":{"@type":"g:Int64","@value":3},"value":"s1"}}` +
+		`]}`
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(js), &a))
+
+	b, err := MarshalGraphBinary(a)
+	require.NoError(t, err)
+	decoded, err := UnmarshalGraphBinary(b)
+	require.NoError(t, err)
+	assert.Equal(t, a, decoded)
+}
+
+func TestUnmarshalGraphBinary_Errors(t *testing.T) {
+	tests := [][]byte{
+		{},
+		{0x01, 0x00, 0x00},
+		{0x03, 0x00, 0x00, 0x00, 0x00, 0x05, 'a'},
+		{0x09, 0x00, 0xff, 0xff, 0xff, 0xff},
+		{0x99, 0x00},
+		{0x01, 0x02, 0x00, 0x00, 0x00, 0x01},
+		{0x01, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00},
+	}
+	for _, b := range tests {
+		_, err := UnmarshalGraphBinary(b)
+		assert.Error(t, err, "%x", b)
+	}
+}
+
+func TestGraphBinaryRequest_RoundTrip(t *testing.T) {
+	req := &GraphBinaryRequest{
+		RequestID: "41d2e28a-20a4-4ab0-b379-d810dede3786",
+		Op:        "eval",
+		Args: NewMap(
+			MapEntry{Key: Attribute{Type: TypeString, Value: "gremlin"}, Value: Attribute{Type: TypeString, Value: "g.V()"}},
+			MapEntry{Key: Attribute{Type: TypeString, Value: "rebindings"}},
+		),
+	}
+	b, err := MarshalGraphBinaryRequest(req)
+	require.NoError(t, err)
+	assert.Equal(t, byte(len(GraphBinaryMimeType)), b[0])
+	assert.Equal(t, GraphBinaryMimeType, string(b[1:len(GraphBinaryMimeType)+1]))
+
+	decoded, err := UnmarshalGraphBinaryRequest(b)
+	require.NoError(t, err)
+	assert.Equal(t, req, decoded)
+
+	_, err = MarshalGraphBinaryRequest(&GraphBinaryRequest{RequestID: "1"})
+	assert.Error(t, err)
+}
+
+func TestGraphBinaryResponse_RoundTrip(t *testing.T) {
+	resp := &GraphBinaryResponse{
+		RequestID:  "41d2e28a-20a4-4ab0-b379-d810dede3786",
+		StatusCode: 200,
+		Data: Attribute{Type: TypeList, Value: List{
+			{Type: TypeString, Value: "s1"},
+			{Type: TypeDate, Value: time.Unix(1600000000, 0).UTC()},
+		}},
+	}
+	b, err := MarshalGraphBinaryResponse(resp)
+	require.NoError(t, err)
+	decoded, err := UnmarshalGraphBinaryResponse(b)
+	require.NoError(t, err)
+	assert.Equal(t, resp, decoded)
+
+	resp = &GraphBinaryResponse{StatusCode: 597, StatusMessage: "No such property: x"}
+	b, err = MarshalGraphBinaryResponse(resp)
+	require.NoError(t, err)
+	decoded, err = UnmarshalGraphBinaryResponse(b)
+	require.NoError(t, err)
+	assert.Equal(t, resp, decoded)
+
+	_, err = UnmarshalGraphBinaryResponse([]byte{0x80})
+	assert.Error(t, err)
+}
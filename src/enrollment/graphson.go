@@ -0,0 +1,231 @@
+package enrollment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+type GraphSONVersion int
+
+const (
+	// GraphSONAuto reads values of any version, it is the default
+	GraphSONAuto GraphSONVersion = 0
+	GraphSONV1   GraphSONVersion = 1
+	GraphSONV2   GraphSONVersion = 2
+	GraphSONV3   GraphSONVersion = 3
+)
+
+var graphSONVersions = map[string]GraphSONVersion{
+	"auto": GraphSONAuto,
+	"v1":   GraphSONV1,
+	"v2":   GraphSONV2,
+	"v3":   GraphSONV3,
+}
+
+// ParseGraphSONVersion reads version name: auto, v1, v2 or v3
+func ParseGraphSONVersion(s string) (GraphSONVersion, error) {
+	v, ok := graphSONVersions[s]
+	if !ok {
+		return GraphSONAuto, fmt.Errorf("unknown GraphSON version: %s", s)
+	}
+	return v, nil
+}
+
+// CheckGraphSONVersion fails a record which is not written in the version.
+// GraphSONV3 is strict: numbers, arrays and objects must be typed where GraphSON 3 writes typed values.
+// GraphSONV1 and GraphSONV2 reject records written in a later version, see DetectGraphSONVersion.
+// GraphSONAuto accepts any record.
+func CheckGraphSONVersion(rec []byte, version GraphSONVersion) error {
+	if version == GraphSONAuto {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(rec))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	if version == GraphSONV3 {
+		return checkTyped(v)
+	}
+	if recVersion := detectVersion(v); recVersion > version {
+		return fmt.Errorf("got GraphSON %d record where GraphSON %d is expected", recVersion, version)
+	}
+	return nil
+}
+
+// checkTyped fails an untyped number, array or object where GraphSON 3 writes a typed value,
+// values inside typed elements, properties, paths, traversers and trees are checked as well
+func checkTyped(v interface{}) error {
+	var m map[string]interface{}
+	switch v := v.(type) {
+	case json.Number:
+		return untypedError("number")
+	case []interface{}:
+		return untypedError("array")
+	case map[string]interface{}:
+		m = v
+	default:
+		return nil
+	}
+	t, ok := m["@type"].(string)
+	if _, hasValue := m["@value"]; !ok || !hasValue || len(m) != 2 {
+		return untypedError("object")
+	}
+	value := m["@value"]
+	switch dbTypes[t] {
+	case TypeList, TypeSet, TypeBulkSet, TypeMap:
+		items, _ := value.([]interface{})
+		return checkTypedItems(items...)
+	case TypeProperty, TypeVertexProperty, TypeEdge, TypePath, TypeTraverser:
+		fields, _ := value.(map[string]interface{})
+		if err := checkTypedItems(fields["id"], fields["value"], fields["inV"], fields["outV"],
+			fields["labels"], fields["objects"], fields["bulk"]); err != nil {
+			return err
+		}
+		props, _ := fields["properties"].(map[string]interface{})
+		for _, p := range props {
+			if err := checkTyped(p); err != nil {
+				return err
+			}
+		}
+	case TypeVertex:
+		fields, _ := value.(map[string]interface{})
+		if err := checkTyped(fields["id"]); err != nil {
+			return err
+		}
+		props, _ := fields["properties"].(map[string]interface{})
+		for _, p := range props {
+			items, _ := p.([]interface{})
+			if err := checkTypedItems(items...); err != nil {
+				return err
+			}
+		}
+	case TypeTree:
+		nodes, _ := value.([]interface{})
+		for _, n := range nodes {
+			node, _ := n.(map[string]interface{})
+			if err := checkTypedItems(node["key"], node["value"]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkTypedItems(items ...interface{}) error {
+	for _, item := range items {
+		if err := checkTyped(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func untypedError(kind string) error {
+	return fmt.Errorf("untyped %s is not allowed in GraphSON 3", kind)
+}
+
+// graphSONV3Types are types GraphSON 2 writes as plain json arrays and objects
+var graphSONV3Types = map[string]bool{
+	string(TypeList):    true,
+	string(TypeMap):     true,
+	string(TypeSet):     true,
+	string(TypeBulkSet): true,
+}
+
+// unmarshalUntyped reads a value without type wrapper:
+// a string as g:String, a bool as g:Boolean, an array as g:List,
+// an integer as g:Int32 when it fits and as g:Int64 otherwise, any other number as g:Double
+func (a *Attribute) unmarshalUntyped(b []byte) error {
+	switch b[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*a = Attribute{Type: TypeString, Value: s}
+	case 't', 'f':
+		var v bool
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		*a = Attribute{Type: TypeBoolean, Value: v}
+	case '[':
+		var l List
+		if err := json.Unmarshal(b, &l); err != nil {
+			return err
+		}
+		*a = Attribute{Type: TypeList, Value: l}
+	default:
+		return a.unmarshalNumber(b)
+	}
+	return nil
+}
+
+func (a *Attribute) unmarshalNumber(b []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	v, err := numberAttribute(n)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+func numberAttribute(n json.Number) (Attribute, error) {
+	if i, err := n.Int64(); err == nil {
+		if i >= math.MinInt32 && i <= math.MaxInt32 {
+			return Attribute{Type: TypeInteger, Value: int32(i)}, nil
+		}
+		return Attribute{Type: TypeLong, Value: i}, nil
+	}
+	f, err := strconv.ParseFloat(n.String(), 64)
+	if err != nil {
+		return Attribute{}, fmt.Errorf("wrong number: %s", n)
+	}
+	return Attribute{Type: TypeDouble, Value: f}, nil
+}
+
+// DetectGraphSONVersion returns the lowest GraphSON version the payload is written in:
+// GraphSON 1 has no typed values, GraphSON 2 has typed values but no g:List, g:Set, g:Map and g:BulkSet
+func DetectGraphSONVersion(b []byte) (GraphSONVersion, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return 0, err
+	}
+	return detectVersion(v), nil
+}
+
+func detectVersion(v interface{}) GraphSONVersion {
+	version := GraphSONV1
+	switch v := v.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if itemVersion := detectVersion(item); itemVersion > version {
+				version = itemVersion
+			}
+		}
+	case map[string]interface{}:
+		if t, ok := v["@type"].(string); ok {
+			if graphSONV3Types[t] {
+				return GraphSONV3
+			}
+			version = GraphSONV2
+		}
+		for _, item := range v {
+			if itemVersion := detectVersion(item); itemVersion > version {
+				version = itemVersion
+			}
+		}
+	}
+	return version
+}
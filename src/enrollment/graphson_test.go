@@ -0,0 +1,153 @@
+package enrollment
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttribute_UnmarshalJSON_Untyped(t *testing.T) {
+	js := []byte(`[1, -2147483649, 2.5, 1e3, true, "s1", null, {"b": [1], "a": {"c": false}}]`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeList, a.Type)
+	l := a.ListValue()
+	require.Len(t, l, 8)
+	assert.Equal(t, Attribute{Type: TypeInteger, Value: int32(1)}, l[0])
+	assert.Equal(t, Attribute{Type: TypeLong, Value: int64(-2147483649)}, l[1])
+	assert.Equal(t, Attribute{Type: TypeDouble, Value: 2.5}, l[2])
+	assert.Equal(t, Attribute{Type: TypeDouble, Value: 1000.0}, l[3])
+	assert.Equal(t, Attribute{Type: TypeBoolean, Value: true}, l[4])
+	assert.Equal(t, Attribute{Type: TypeString, Value: "s1"}, l[5])
+	assert.Equal(t, Attribute{}, l[6])
+
+	m := l[7].MapValue()
+	require.Equal(t, 2, m.Len())
+	assert.Equal(t, "b", m.Entries()[0].Key.StringValue())
+	assert.Equal(t, int32(1), m.Get("b").ListValue()[0].Int32Value())
+	assert.False(t, m.Get("a").MapValue().Get("c").BoolValue())
+}
+
+func TestAttribute_UnmarshalJSON_GraphSON2(t *testing.T) {
+	js := []byte(`[{"sitter_id": ["s1"], "years_of_exp": [{"@type":"g:Int32","@value":5}], "avg_rank": [{"@type":"g:Double","@value":4.5}]}]`)
+	var providers []testProvider
+	err := UnmarshalInto([][]byte{js}, &providers)
+	require.NoError(t, err)
+	require.Len(t, providers, 1)
+	assert.Equal(t, "s1", providers[0].SitterID)
+	assert.Equal(t, int64(5), providers[0].YearsOfExp)
+	assert.Equal(t, 4.5, providers[0].AvgRank)
+}
+
+func TestAttribute_UnmarshalJSON_GraphSON1Vertex(t *testing.T) {
+	js := []byte(`{"id": 1, "label": "provider", "type": "vertex", "properties": {` +
+		`"sitter_id": [{"id": 10, "value": "s1"}], "years_of_exp": [{"id": 11, "value": 5}]}}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeVertex, a.Type)
+	v := a.VertexValue()
+	assert.Equal(t, int32(1), v.ID.Int32Value())
+	assert.Equal(t, "provider", v.Label)
+	p, ok := v.Property("sitter_id")
+	require.True(t, ok)
+	assert.Equal(t, "sitter_id", p.Label)
+	assert.Equal(t, int32(10), p.ID.Int32Value())
+	assert.Equal(t, "s1", p.Value.StringValue())
+	assert.Equal(t, int32(5), v.PropertyValue("years_of_exp").Int32Value())
+}
+
+func TestAttribute_UnmarshalJSON_GraphSON1Edge(t *testing.T) {
+	js := []byte(`{"id": 7, "label": "provides", "type": "edge", "inVLabel": "service", "outVLabel": "provider",` +
+		`"inV": 2, "outV": 1, "properties": {"min_rate": 12.5}}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeEdge, a.Type)
+	e := a.EdgeValue()
+	assert.Equal(t, "provides", e.Label)
+	assert.Equal(t, int32(2), e.InV.Int32Value())
+	assert.Equal(t, Property{Key: "min_rate", Value: Attribute{Type: TypeDouble, Value: 12.5}}, e.Properties["min_rate"])
+}
+
+func TestDetectGraphSONVersion(t *testing.T) {
+	tests := []struct {
+		js      string
+		version GraphSONVersion
+	}{
+		{`["s1", 1]`, GraphSONV1},
+		{`{"id": 1, "type": "vertex"}`, GraphSONV1},
+		{`[{"@type":"g:Int32","@value":1}]`, GraphSONV2},
+		{`{"a": {"@type":"g:Vertex","@value":{}}}`, GraphSONV2},
+		{`{"@type":"g:List","@value":["s1"]}`, GraphSONV3},
+		{`[{"b": {"@type":"g:Set","@value":[]}}]`, GraphSONV3},
+	}
+	for _, test := range tests {
+		version, err := DetectGraphSONVersion([]byte(test.js))
+		require.NoError(t, err)
+		assert.Equal(t, test.version, version, test.js)
+	}
+	_, err := DetectGraphSONVersion([]byte(`[`))
+	assert.Error(t, err)
+}
+
+func TestParseGraphSONVersion(t *testing.T) {
+	v, err := ParseGraphSONVersion("v3")
+	require.NoError(t, err)
+	assert.Equal(t, GraphSONV3, v)
+	v, err = ParseGraphSONVersion("auto")
+	require.NoError(t, err)
+	assert.Equal(t, GraphSONAuto, v)
+	_, err = ParseGraphSONVersion("3.0")
+	assert.Error(t, err)
+}
+
+func TestUnmarshalListVersion_V3(t *testing.T) {
+	items, err := UnmarshalListVersion([][]byte{[]byte(`{"@type":"g:List","@value":["s1",true,{"@type":"g:Int32","@value":1},` +
+		`{"@type":"g:Map","@value":["k",{"@type":"g:Double","@value":2.5}]}]}`)}, GraphSONV3)
+	require.NoError(t, err)
+	assert.Len(t, items, 4)
+
+	for _, js := range []string{
+		`["s1"]`,
+		`{"@type":"g:List","@value":[1]}`,
+		`{"@type":"g:List","@value":[["s1"]]}`,
+		`{"@type":"g:List","@value":[{"k":"v"}]}`,
+		`{"@type":"g:List","@value":[{"@type":"g:Traverser","@value":{"bulk":1,"value":"s1"}}]}`,
+		`{"@type":"g:List","@value":[{"@type":"g:Vertex","@value":{"id":{"@type":"g:Int64","@value":1},"label":"provider",` +
+			`"properties":{"years_of_exp":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":10},"value":5}}]}}}]}`,
+	} {
+		_, err := UnmarshalListVersion([][]byte{[]byte(js)}, GraphSONV3)
+		assert.Error(t, err, js)
+		err = StreamRecordsVersion([][]byte{[]byte(js)}, GraphSONV3, func(Attribute) error { return nil })
+		assert.Error(t, err, js)
+
+		// the version is an option of the call, other calls read any version
+		_, err = UnmarshalList([][]byte{[]byte(js)})
+		assert.NoError(t, err, js)
+	}
+}
+
+func TestUnmarshalListVersion_V2(t *testing.T) {
+	items, err := UnmarshalListVersion([][]byte{[]byte(`{"@type":"g:List","@value":["s1"]}`)}, GraphSONV2)
+	assert.Error(t, err)
+	assert.Empty(t, items)
+	err = StreamRecordsVersion([][]byte{[]byte(`[{"a":{"@type":"g:Set","@value":[]}}]`)}, GraphSONV2, func(Attribute) error { return nil })
+	assert.Error(t, err)
+
+	items, err = UnmarshalListVersion([][]byte{[]byte(`[{"@type":"g:Int32","@value":1}, 2]`)}, GraphSONV2)
+	require.NoError(t, err)
+	assert.Equal(t, List{{Type: TypeInteger, Value: int32(1)}, {Type: TypeInteger, Value: int32(2)}}, items)
+}
+
+func TestUnmarshalListVersion_V1(t *testing.T) {
+	_, err := UnmarshalListVersion([][]byte{[]byte(`[{"@type":"g:Int32","@value":1}]`)}, GraphSONV1)
+	assert.Error(t, err)
+	items, err := UnmarshalListVersion([][]byte{[]byte(`[1, {"id": 1, "label": "provider", "type": "vertex"}]`)}, GraphSONV1)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, TypeVertex, items[1].Type)
+}
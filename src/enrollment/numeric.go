@@ -0,0 +1,163 @@
+package enrollment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	errNotInteger = errors.New("not an integer")
+	errNotNumber  = errors.New("not a number")
+)
+
+// maxExactFloat64 is the biggest integer every smaller integer of which is exact in float64
+const maxExactFloat64 = 1 << 53
+
+// integerValue returns the go integer backing the attribute, whatever its size is
+func integerValue(a Attribute) (int64, bool) {
+	switch v := a.Value.(type) {
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// floatValue returns the go number backing the attribute, float32 included, as float64
+func floatValue(a Attribute) (float64, bool) {
+	switch v := a.Value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	}
+	n, ok := integerValue(a)
+	return float64(n), ok
+}
+
+// coerceInt64 reads an integer for Decode and CoerceInt64:
+// g:Int32, g:Long, gx:Byte and gx:Int16 whatever go integer backs them, gx:BigInteger in int64 range
+// and g:String holding an integer. Floating point numbers are errNotInteger.
+func coerceInt64(a Attribute) (int64, error) {
+	switch a.Type {
+	case TypeInteger, TypeLong, TypeByte, TypeInt16:
+		if n, ok := integerValue(a); ok {
+			return n, nil
+		}
+	case TypeBigInteger:
+		n := a.BigIntValue()
+		if n == nil {
+			break
+		}
+		if !n.IsInt64() {
+			return 0, errors.New("value out of range")
+		}
+		return n.Int64(), nil
+	case TypeString:
+		n, err := strconv.ParseInt(strings.TrimSpace(a.StringValue()), 10, 64)
+		if err != nil {
+			return 0, errors.New(numberErrorReason(err))
+		}
+		return n, nil
+	}
+	return 0, errNotInteger
+}
+
+// coerceFloat64 reads a number for Decode and CoerceFloat64:
+// g:Float and g:Double whatever go float backs them, g:String holding a finite decimal number
+// and integers read by coerceInt64 which are exact in float64
+func coerceFloat64(a Attribute) (float64, error) {
+	switch a.Type {
+	case TypeFloat, TypeDouble:
+		if f, ok := floatValue(a); ok {
+			return f, nil
+		}
+		return 0, errNotNumber
+	case TypeString:
+		return parseFloat64(strings.TrimSpace(a.StringValue()))
+	}
+	n, err := coerceInt64(a)
+	if err == errNotInteger {
+		return 0, errNotNumber
+	}
+	if err != nil {
+		return 0, err
+	}
+	if n > maxExactFloat64 || n < -maxExactFloat64 {
+		return 0, errors.New("value loses precision")
+	}
+	return float64(n), nil
+}
+
+// decimalValue reads numbers exactly, floating point numbers are read by their shortest representation
+func decimalValue(a Attribute) (Decimal, bool) {
+	switch a.Type {
+	case TypeBigDecimal:
+		return a.DecimalValue(), true
+	case TypeBigInteger:
+		return Decimal{Unscaled: a.BigIntValue()}, a.BigIntValue() != nil
+	case TypeFloat, TypeDouble:
+		f, ok := floatValue(a)
+		if !ok || math.IsNaN(f) || math.IsInf(f, 0) {
+			return Decimal{}, false
+		}
+		bitSize := 64
+		if a.Type == TypeFloat {
+			bitSize = 32
+		}
+		d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, bitSize))
+		return d, err == nil
+	case TypeString:
+		d, err := ParseDecimal(strings.TrimSpace(a.StringValue()))
+		return d, err == nil
+	}
+	n, err := coerceInt64(a)
+	return NewDecimal(n, 0), err == nil
+}
+
+func bigIntValue(a Attribute) (*big.Int, bool) {
+	switch a.Type {
+	case TypeBigInteger:
+		return a.BigIntValue(), a.BigIntValue() != nil
+	case TypeString:
+		return new(big.Int).SetString(strings.TrimSpace(a.StringValue()), 10)
+	}
+	n, err := coerceInt64(a)
+	return big.NewInt(n), err == nil
+}
+
+// parseFloat64 reads finite decimal numbers only, strconv.ParseFloat reads NaN, infinity and hex floats as well
+func parseFloat64(s string) (float64, error) {
+	if strings.ContainsAny(s, "xX") {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, errors.New(numberErrorReason(err))
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	return f, nil
+}
+
+func numberErrorReason(err error) string {
+	if e, ok := err.(*strconv.NumError); ok {
+		if e.Err == strconv.ErrRange {
+			return "value out of range"
+		}
+		return fmt.Sprintf("%q is not a number", e.Num)
+	}
+	return err.Error()
+}
@@ -0,0 +1,148 @@
+package enrollment
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidPageToken  = errors.New("invalid page token")
+	ErrPageTokenMismatch = errors.New("page token does not match requested sort order")
+	ErrNoPageTokenSecret = errors.New("page token secret is not set")
+)
+
+var pageTokenSecret []byte
+
+// SetPageTokenSecret sets the key page tokens are signed with.
+// It must be set before the first search and be the same on all instances,
+// page tokens are neither issued nor accepted without it.
+func SetPageTokenSecret(secret []byte) error {
+	if len(secret) == 0 {
+		return ErrNoPageTokenSecret
+	}
+	pageTokenSecret = secret
+	return nil
+}
+
+// pageToken is the last seen position of a page: sort key and sitter_id of the last provider
+type pageToken struct {
+	Field     SortField     `json:"f,omitempty"`
+	Direction SortDirection `json:"d,omitempty"`
+	Key       *tokenValue   `json:"k,omitempty"`
+	SitterID  tokenValue    `json:"s"`
+}
+
+// tokenValue keeps db type of the value so it is compared with the same type in the graph
+type tokenValue struct {
+	Type  DBType `json:"t"`
+	Value string `json:"v"`
+}
+
+func newTokenValue(a Attribute) (tokenValue, error) {
+	switch v := a.Value.(type) {
+	case string:
+		return tokenValue{Type: TypeString, Value: v}, nil
+	case int32:
+		return tokenValue{Type: TypeInteger, Value: strconv.FormatInt(int64(v), 10)}, nil
+	case int64:
+		return tokenValue{Type: TypeLong, Value: strconv.FormatInt(v, 10)}, nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return tokenValue{}, fmt.Errorf("unsupported page token value: %v", v)
+		}
+		if a.Type == TypeDouble {
+			return tokenValue{Type: TypeDouble, Value: strconv.FormatFloat(v, 'g', -1, 64)}, nil
+		}
+		return tokenValue{Type: TypeFloat, Value: strconv.FormatFloat(v, 'g', -1, 32)}, nil
+	}
+	return tokenValue{}, fmt.Errorf("unsupported page token value type: %s", a.Type)
+}
+
+// number returns the decimal form of a number value, which is read back by its type in the script, see bindToken
+func (v tokenValue) number() (string, error) {
+	switch v.Type {
+	case TypeInteger:
+		if _, err := strconv.ParseInt(v.Value, 10, 32); err != nil {
+			return "", ErrInvalidPageToken
+		}
+		return v.Value, nil
+	case TypeLong:
+		if _, err := strconv.ParseInt(v.Value, 10, 64); err != nil {
+			return "", ErrInvalidPageToken
+		}
+		return v.Value, nil
+	case TypeFloat:
+		return floatNumber(v.Value, 32)
+	case TypeDouble:
+		return floatNumber(v.Value, 64)
+	}
+	return "", ErrInvalidPageToken
+}
+
+// floatNumber renders a finite decimal float as java Float.valueOf and Double.valueOf read it,
+// hex floats and NaN or Inf accepted by strconv are rejected
+func floatNumber(s string, bitSize int) (string, error) {
+	if strings.ContainsAny(s, "xX") {
+		return "", ErrInvalidPageToken
+	}
+	f, err := strconv.ParseFloat(s, bitSize)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", ErrInvalidPageToken
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize), nil
+}
+
+func encodePageToken(token *pageToken) (string, error) {
+	if len(pageTokenSecret) == 0 {
+		return "", ErrNoPageTokenSecret
+	}
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(signPageToken(payload)), nil
+}
+
+func decodePageToken(s string) (*pageToken, error) {
+	if len(pageTokenSecret) == 0 {
+		return nil, ErrNoPageTokenSecret
+	}
+	enc := base64.RawURLEncoding
+	parts := strings.Split(s, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidPageToken
+	}
+	payload, err := enc.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	signature, err := enc.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	if !hmac.Equal(signature, signPageToken(payload)) {
+		return nil, ErrInvalidPageToken
+	}
+	var token pageToken
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&token); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	return &token, nil
+}
+
+func signPageToken(payload []byte) []byte {
+	mac := hmac.New(sha256.New, pageTokenSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
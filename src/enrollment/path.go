@@ -0,0 +1,306 @@
+package enrollment
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PathError tells which segment of the path given to Attribute.Get cannot be followed
+type PathError struct {
+	// Path is the path up to the failed segment, e.g. 0.sitter_id.3
+	Path string
+	// Type is the type of the value the segment is applied to
+	Type   DBType
+	Reason string
+}
+
+func (e *PathError) Error() string {
+	t := string(e.Type)
+	if len(t) == 0 {
+		t = nullValue
+	}
+	return fmt.Sprintf("cannot get %s from %s: %s", e.Path, t, e.Reason)
+}
+
+// Get follows the path of dot separated segments, e.g. 0.sitter_id.0.value:
+// a segment is an index in g:List, g:Set, g:BulkSet and g:Path objects, a key of g:Map and g:Tree,
+// a property key or id and label of g:Vertex and g:Edge, inV, outV, inVLabel and outVLabel of g:Edge,
+// id, label and value of g:VertexProperty, key and value of g:Property, bulk and value of g:Traverser.
+// A property key of a vertex gives a g:List of its vertex properties.
+// A dot in a key is written as \. and a backslash as \\. An empty path returns the attribute itself.
+func (a Attribute) Get(path string) (Attribute, error) {
+	if len(path) == 0 {
+		return a, nil
+	}
+	segments, err := splitPath(path)
+	if err != nil {
+		return Attribute{}, &PathError{Path: path, Type: a.Type, Reason: err.Error()}
+	}
+	var done []string
+	for _, s := range segments {
+		done = append(done, strings.Replace(strings.Replace(s, `\`, `\\`, -1), ".", `\.`, -1))
+		next, err := a.child(s)
+		if err != nil {
+			return Attribute{}, &PathError{Path: strings.Join(done, "."), Type: a.Type, Reason: err.Error()}
+		}
+		a = next
+	}
+	return a, nil
+}
+
+// splitPath splits the path by dots which are not escaped
+func splitPath(path string) ([]string, error) {
+	var segments []string
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '\\':
+			if i+1 == len(path) {
+				return nil, fmt.Errorf("path ends with escape")
+			}
+			i++
+			b.WriteByte(path[i])
+		case '.':
+			segments = append(segments, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(path[i])
+		}
+	}
+	return append(segments, b.String()), nil
+}
+
+// child returns the value the segment points to in a
+func (a Attribute) child(s string) (Attribute, error) {
+	switch a.Type {
+	case TypeList, TypeSet:
+		return index(a.ListValue(), s)
+	case TypeBulkSet:
+		return bulkSetIndex(a.BulkSetValue(), s)
+	case TypeMap:
+		if v, ok := a.MapValue().Lookup(s); ok {
+			return v, nil
+		}
+	case TypeTree:
+		for _, n := range a.TreeValue() {
+			if n.Key.ToString() == s {
+				return Attribute{Type: TypeTree, Value: n.Children}, nil
+			}
+		}
+	case TypePath:
+		return index(a.PathValue().Objects, s)
+	case TypeVertex:
+		v := a.VertexValue()
+		switch s {
+		case string(TID):
+			return v.ID, nil
+		case string(TLabel):
+			return Attribute{Type: TypeString, Value: v.Label}, nil
+		}
+		if props, ok := v.Properties[s]; ok {
+			l := make(List, 0, len(props))
+			for _, p := range props {
+				l = append(l, Attribute{Type: TypeVertexProperty, Value: p})
+			}
+			return Attribute{Type: TypeList, Value: l}, nil
+		}
+	case TypeEdge:
+		e := a.EdgeValue()
+		switch s {
+		case string(TID):
+			return e.ID, nil
+		case string(TLabel):
+			return Attribute{Type: TypeString, Value: e.Label}, nil
+		case "inV":
+			return e.InV, nil
+		case "outV":
+			return e.OutV, nil
+		case "inVLabel":
+			return Attribute{Type: TypeString, Value: e.InVLabel}, nil
+		case "outVLabel":
+			return Attribute{Type: TypeString, Value: e.OutVLabel}, nil
+		}
+		if p, ok := e.Properties[s]; ok {
+			return Attribute{Type: TypeProperty, Value: p}, nil
+		}
+	case TypeVertexProperty:
+		p := a.VertexPropertyValue()
+		switch s {
+		case string(TID):
+			return p.ID, nil
+		case string(TLabel):
+			return Attribute{Type: TypeString, Value: p.Label}, nil
+		case string(TValue):
+			return p.Value, nil
+		}
+	case TypeProperty:
+		p := a.PropertyValue()
+		switch s {
+		case string(TKey):
+			return Attribute{Type: TypeString, Value: p.Key}, nil
+		case string(TValue):
+			return p.Value, nil
+		}
+	case TypeTraverser:
+		tr := a.TraverserValue()
+		switch s {
+		case "bulk":
+			return Attribute{Type: TypeLong, Value: tr.Bulk}, nil
+		case string(TValue):
+			return tr.Value, nil
+		}
+	default:
+		return Attribute{}, fmt.Errorf("value has no elements")
+	}
+	return Attribute{}, fmt.Errorf("no such key: %s", s)
+}
+
+func index(l List, s string) (Attribute, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 0 {
+		return Attribute{}, fmt.Errorf("wrong index: %s", s)
+	}
+	if i >= len(l) {
+		return Attribute{}, fmt.Errorf("index %d out of range of %d items", i, len(l))
+	}
+	return l[i], nil
+}
+
+// bulkSetIndex finds the item by index of the expanded bulk set, items are not expanded
+func bulkSetIndex(set BulkSet, s string) (Attribute, error) {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || i < 0 {
+		return Attribute{}, fmt.Errorf("wrong index: %s", s)
+	}
+	var n int64
+	for _, item := range set {
+		if item.Bulk <= 0 {
+			continue
+		}
+		if i < n+item.Bulk {
+			return item.Value, nil
+		}
+		n += item.Bulk
+	}
+	return Attribute{}, fmt.Errorf("index %d out of range of %d items", i, n)
+}
+
+// Interface converts the attribute to plain go values, which encoding/json writes as GraphSON 1 does:
+// g:List, g:Set and g:BulkSet are []interface{}, g:Map and g:Tree are map[string]interface{} with string forms of keys,
+// elements are maps of GraphSON 1 fields, enums and chars are strings, gx:Duration is ISO-8601 string,
+// gx:BigDecimal is json.Number, so it is exact, NaN and infinite floats are "NaN", "Infinity" and "-Infinity"
+// strings as encoding/json cannot write them. Other values are kept as they are.
+func (a Attribute) Interface() interface{} {
+	switch a.Type {
+	case TypeList, TypeSet:
+		return listInterface(a.ListValue())
+	case TypeBulkSet:
+		return listInterface(a.BulkSetValue().List())
+	case TypeMap:
+		m := a.MapValue()
+		res := make(map[string]interface{}, m.Len())
+		for _, e := range m.Entries() {
+			key := e.Key.ToString()
+			if _, ok := res[key]; !ok {
+				res[key] = e.Value.Interface()
+			}
+		}
+		return res
+	case TypeTree:
+		return treeInterface(a.TreeValue())
+	case TypeT:
+		return string(a.TValue())
+	case TypeDirection:
+		return string(a.DirectionValue())
+	case TypeChar:
+		return string(a.CharValue())
+	case TypeDuration:
+		return FormatISODuration(a.DurationValue())
+	case TypeBigDecimal:
+		return json.Number(a.DecimalValue().String())
+	case TypeProperty:
+		p := a.PropertyValue()
+		return map[string]interface{}{"key": p.Key, "value": p.Value.Interface()}
+	case TypeVertexProperty:
+		return vertexPropertyInterface(a.VertexPropertyValue())
+	case TypeVertex:
+		v := a.VertexValue()
+		props := make(map[string]interface{}, len(v.Properties))
+		for key, values := range v.Properties {
+			l := make([]interface{}, 0, len(values))
+			for _, p := range values {
+				l = append(l, vertexPropertyInterface(p))
+			}
+			props[key] = l
+		}
+		return map[string]interface{}{"id": v.ID.Interface(), "label": v.Label, "type": "vertex", "properties": props}
+	case TypeEdge:
+		e := a.EdgeValue()
+		props := make(map[string]interface{}, len(e.Properties))
+		for key, p := range e.Properties {
+			props[key] = p.Value.Interface()
+		}
+		return map[string]interface{}{
+			"id": e.ID.Interface(), "label": e.Label, "type": "edge",
+			"inV": e.InV.Interface(), "inVLabel": e.InVLabel, "outV": e.OutV.Interface(), "outVLabel": e.OutVLabel,
+			"properties": props,
+		}
+	case TypePath:
+		p := a.PathValue()
+		labels := make([]interface{}, 0, len(p.Labels))
+		for _, names := range p.Labels {
+			l := make([]interface{}, 0, len(names))
+			for _, name := range names {
+				l = append(l, name)
+			}
+			labels = append(labels, l)
+		}
+		return map[string]interface{}{"labels": labels, "objects": listInterface(p.Objects)}
+	case TypeTraverser:
+		tr := a.TraverserValue()
+		return map[string]interface{}{"bulk": tr.Bulk, "value": tr.Value.Interface()}
+	}
+	switch v := a.Value.(type) {
+	case float64:
+		return floatInterface(v, a.Value)
+	case float32:
+		return floatInterface(float64(v), a.Value)
+	}
+	return a.Value
+}
+
+// floatInterface returns non-finite f as GraphSON writes it and v otherwise
+func floatInterface(f float64, v interface{}) interface{} {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return v
+}
+
+func listInterface(l List) []interface{} {
+	res := make([]interface{}, 0, len(l))
+	for _, item := range l {
+		res = append(res, item.Interface())
+	}
+	return res
+}
+
+func treeInterface(tr Tree) map[string]interface{} {
+	res := make(map[string]interface{}, len(tr))
+	for _, n := range tr {
+		res[n.Key.ToString()] = treeInterface(n.Children)
+	}
+	return res
+}
+
+func vertexPropertyInterface(p VertexProperty) map[string]interface{} {
+	return map[string]interface{}{"id": p.ID.Interface(), "label": p.Label, "value": p.Value.Interface()}
+}
@@ -0,0 +1,149 @@
+package enrollment
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pathResult = `{"@type":"g:List","@value":[{"@type":"g:Map","@value":[` +
+	`"sitter_id",{"@type":"g:List","@value":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":10},"value":"s1","label":"sitter_id"}}]},` +
+	`"a.b","dotted",` +
+	`"provider",{"@type":"g:Vertex","@value":{"id":{"@type":"g:Int64","@value":1},"label":"provider","properties":{` +
+	`"service":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":11},"value":"childCare","label":"service"}}]}}},` +
+	`"knows",{"@type":"g:Edge","@value":{"id":{"@type":"g:Int64","@value":7},"label":"knows","inVLabel":"provider","outVLabel":"owner",` +
+	`"inV":{"@type":"g:Int64","@value":1},"outV":{"@type":"g:Int64","@value":2},` +
+	`"properties":{"since":{"@type":"g:Property","@value":{"key":"since","value":{"@type":"g:Int32","@value":2019}}}}}},` +
+	`"years",{"@type":"g:Int32","@value":5}` +
+	`]}]}`
+
+func TestAttribute_Get(t *testing.T) {
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(pathResult), &a))
+
+	tests := []struct {
+		path  string
+		value Attribute
+	}{
+		{"0.sitter_id.0.value", Attribute{Type: TypeString, Value: "s1"}},
+		{"0.sitter_id.0.id", Attribute{Type: TypeLong, Value: int64(10)}},
+		{`0.a\.b`, Attribute{Type: TypeString, Value: "dotted"}},
+		{"0.provider.id", Attribute{Type: TypeLong, Value: int64(1)}},
+		{"0.provider.label", Attribute{Type: TypeString, Value: "provider"}},
+		{"0.provider.service.0.value", Attribute{Type: TypeString, Value: "childCare"}},
+		{"0.knows.outV", Attribute{Type: TypeLong, Value: int64(2)}},
+		{"0.knows.inVLabel", Attribute{Type: TypeString, Value: "provider"}},
+		{"0.knows.since.value", Attribute{Type: TypeInteger, Value: int32(2019)}},
+		{"0.knows.since.key", Attribute{Type: TypeString, Value: "since"}},
+		{"", a},
+	}
+	for _, test := range tests {
+		v, err := a.Get(test.path)
+		require.NoError(t, err, test.path)
+		assert.Equal(t, test.value, v, test.path)
+	}
+}
+
+func TestAttribute_Get_Error(t *testing.T) {
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(pathResult), &a))
+
+	tests := []struct {
+		path string
+		err  string
+	}{
+		{"1.sitter_id", "cannot get 1 from g:List: index 1 out of range of 1 items"},
+		{"x", "cannot get x from g:List: wrong index: x"},
+		{"0.zip", "cannot get 0.zip from g:Map: no such key: zip"},
+		{"0.years.value", "cannot get 0.years.value from g:Int32: value has no elements"},
+		{"0.provider.rate", "cannot get 0.provider.rate from g:Vertex: no such key: rate"},
+		{`0.a\.b.c`, `cannot get 0.a\.b.c from g:String: value has no elements`},
+		{`0.a\`, `cannot get 0.a\ from g:List: path ends with escape`},
+	}
+	for _, test := range tests {
+		_, err := a.Get(test.path)
+		require.Error(t, err, test.path)
+		assert.EqualError(t, err, test.err, test.path)
+		var pathErr *PathError
+		assert.True(t, errors.As(err, &pathErr), test.path)
+	}
+
+	_, err := Attribute{}.Get("0")
+	assert.EqualError(t, err, "cannot get 0 from null: value has no elements")
+}
+
+func TestAttribute_Get_Containers(t *testing.T) {
+	js := `{"@type":"g:List","@value":[` +
+		`{"@type":"g:BulkSet","@value":["s1",{"@type":"g:Int64","@value":2},"s2",{"@type":"g:Int64","@value":1}]},` +
+		`{"@type":"g:Path","@value":{"labels":{"@type":"g:List","@value":[{"@type":"g:Set","@value":["p"]}]},"objects":{"@type":"g:List","@value":["s1"]}}},` +
+		`{"@type":"g:Tree","@value":[{"key":"78704","value":{"@type":"g:Tree","@value":[{"key":"s1","value":{"@type":"g:Tree","@value":[]}}]}}]},` +
+		`{"@type":"g:Traverser","@value":{"bulk":{"@type":"g:Int64","@value":3},"value":"s1"}}]}`
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(js), &a))
+
+	v, err := a.Get("0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "s1", v.StringValue())
+	v, err = a.Get("0.2")
+	require.NoError(t, err)
+	assert.Equal(t, "s2", v.StringValue())
+	_, err = a.Get("0.3")
+	assert.EqualError(t, err, "cannot get 0.3 from g:BulkSet: index 3 out of range of 3 items")
+
+	// a huge bulk is not expanded
+	big := Attribute{Type: TypeBulkSet, Value: BulkSet{{Value: Attribute{Type: TypeString, Value: "s1"}, Bulk: math.MaxInt64 - 1}}}
+	v, err = big.Get("9223372036854775805")
+	require.NoError(t, err)
+	assert.Equal(t, "s1", v.StringValue())
+
+	v, err = a.Get("1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "s1", v.StringValue())
+
+	v, err = a.Get("2.78704.s1")
+	require.NoError(t, err)
+	assert.Equal(t, TypeTree, v.Type)
+	assert.Empty(t, v.TreeValue())
+
+	v, err = a.Get("3.bulk")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), v.Int64Value())
+}
+
+func TestAttribute_Interface(t *testing.T) {
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(pathResult), &a))
+
+	b, err := json.Marshal(a.Interface())
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"sitter_id":[{"id":10,"label":"sitter_id","value":"s1"}],
+		"a.b":"dotted",
+		"provider":{"id":1,"label":"provider","type":"vertex","properties":{"service":[{"id":11,"label":"service","value":"childCare"}]}},
+		"knows":{"id":7,"label":"knows","type":"edge","inV":1,"inVLabel":"provider","outV":2,"outVLabel":"owner","properties":{"since":2019}},
+		"years":5
+	}]`, string(b))
+
+	d, err := ParseDecimal("12.50")
+	require.NoError(t, err)
+	assert.Equal(t, json.Number("12.50"), Attribute{Type: TypeBigDecimal, Value: d}.Interface())
+	assert.Equal(t, "PT24H1.5S", Attribute{Type: TypeDuration, Value: 24*time.Hour + 1500*time.Millisecond}.Interface())
+	assert.Equal(t, "id", Attribute{Type: TypeT, Value: TID}.Interface())
+	assert.Nil(t, Attribute{}.Interface())
+
+	floats := Attribute{Type: TypeList, Value: List{
+		{Type: TypeDouble, Value: math.NaN()},
+		{Type: TypeDouble, Value: math.Inf(1)},
+		{Type: TypeFloat, Value: math.Inf(-1)},
+		{Type: TypeFloat, Value: float32(math.Inf(1))},
+		{Type: TypeDouble, Value: 2.5},
+	}}
+	b, err = json.Marshal(floats.Interface())
+	require.NoError(t, err)
+	assert.JSONEq(t, `["NaN","Infinity","-Infinity","Infinity",2.5]`, string(b))
+}
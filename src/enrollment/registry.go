@@ -0,0 +1,153 @@
+package enrollment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// TypeDecoder reads @value of a custom type, e.g. janusgraph:RelationIdentifier, into a go value
+type TypeDecoder func(raw json.RawMessage) (interface{}, error)
+
+// UnknownTypePolicy tells what is done with a value of a type which is neither built in nor registered
+type UnknownTypePolicy int
+
+const (
+	// UnknownTypeFail fails unmarshal of the whole record, it is the default
+	UnknownTypeFail UnknownTypePolicy = iota
+	// UnknownTypeRaw keeps @value as json, see RawValue
+	UnknownTypeRaw
+	// UnknownTypeSkip drops the value from lists, sets and maps, elsewhere it is read as null
+	UnknownTypeSkip
+)
+
+var unknownTypePolicies = map[string]UnknownTypePolicy{
+	"fail": UnknownTypeFail,
+	"raw":  UnknownTypeRaw,
+	"skip": UnknownTypeSkip,
+}
+
+// ParseUnknownTypePolicy reads policy name: fail, raw or skip
+func ParseUnknownTypePolicy(s string) (UnknownTypePolicy, error) {
+	p, ok := unknownTypePolicies[s]
+	if !ok {
+		return UnknownTypeFail, fmt.Errorf("unknown type policy: %s", s)
+	}
+	return p, nil
+}
+
+var registry = struct {
+	sync.RWMutex
+	decoders map[string]TypeDecoder
+	policy   UnknownTypePolicy
+}{decoders: make(map[string]TypeDecoder)}
+
+// errSkipped is returned when a value is dropped by UnknownTypeSkip
+var errSkipped = errors.New("value of unknown type is skipped")
+
+// RegisterType makes values of the custom type name read by decode,
+// they are read as Attribute with Type name and Value returned by decode.
+// Marshal writes the value back with encoding/json, so decode should read what json.Marshal writes.
+// Built in g: types cannot be replaced.
+func RegisterType(name string, decode TypeDecoder) error {
+	if len(name) == 0 || decode == nil {
+		return errors.New("type name and decoder are required")
+	}
+	if _, ok := dbTypes[name]; ok {
+		return fmt.Errorf("%s is a built in type", name)
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	registry.decoders[name] = decode
+	return nil
+}
+
+// UnregisterType removes decoder of the custom type name
+func UnregisterType(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.decoders, name)
+}
+
+// SetUnknownTypePolicy sets what is done with values of types which are neither built in nor registered
+func SetUnknownTypePolicy(p UnknownTypePolicy) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.policy = p
+}
+
+func unknownTypePolicy() UnknownTypePolicy {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.policy
+}
+
+// unmarshalCustomType reads a type missing in dbTypes by its registered decoder or by unknown type policy
+func (a *Attribute) unmarshalCustomType(rec *dbRecord) error {
+	registry.RLock()
+	decode, ok := registry.decoders[rec.Type]
+	policy := registry.policy
+	registry.RUnlock()
+	if ok {
+		v, err := decode(rec.Raw)
+		if err != nil {
+			return fmt.Errorf("wrong %s value: %v", rec.Type, err)
+		}
+		*a = Attribute{Type: DBType(rec.Type), Value: v}
+		return nil
+	}
+	switch policy {
+	case UnknownTypeRaw:
+		*a = Attribute{Type: DBType(rec.Type), Value: rec.Raw}
+		return nil
+	case UnknownTypeSkip:
+		return errSkipped
+	}
+	return fmt.Errorf("unknown type: %s", rec.Type)
+}
+
+// isCustomType tells that t is not a built in type, so it is read by a registered decoder or kept raw
+func isCustomType(t DBType) bool {
+	if len(t) == 0 {
+		return false
+	}
+	_, ok := dbTypes[string(t)]
+	return !ok
+}
+
+// RawValue returns @value of a type kept by UnknownTypeRaw
+func (a Attribute) RawValue() json.RawMessage {
+	v, ok := a.Value.(json.RawMessage)
+	if !ok {
+		return nil
+	}
+	return v
+}
+
+// unmarshalItems reads json array calling fn for every item, skipped tells that the item is dropped by UnknownTypeSkip
+func unmarshalItems(b []byte, fn func(a Attribute, skipped bool)) error {
+	if unknownTypePolicy() != UnknownTypeSkip {
+		var items []Attribute
+		if err := json.Unmarshal(b, &items); err != nil {
+			return err
+		}
+		for _, a := range items {
+			fn(a, false)
+		}
+		return nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	for _, raw := range items {
+		var a Attribute
+		err := a.unmarshal(raw)
+		if err != nil && err != errSkipped {
+			return err
+		}
+		fn(a, err == errSkipped)
+	}
+	return nil
+}
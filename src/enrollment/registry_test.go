@@ -0,0 +1,132 @@
+package enrollment
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const unknownTypesResult = `{"@type":"g:List","@value":["s1",` +
+	`{"@type":"janusgraph:RelationIdentifier","@value":{"relationId":"4r6-39s-69zp-3c8"}},` +
+	`{"@type":"g:Map","@value":["sitter_id","s2","location",{"@type":"janusgraph:Geoshape","@value":{"coordinates":[-97.7,30.2]}}]},` +
+	`{"@type":"g:Property","@value":{"key":"location","value":{"@type":"janusgraph:Geoshape","@value":{"coordinates":[-97.7,30.2]}}}}]}`
+
+type relationIdentifier struct {
+	RelationID string `json:"relationId"`
+}
+
+func decodeRelationIdentifier(raw json.RawMessage) (interface{}, error) {
+	var v relationIdentifier
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	if len(v.RelationID) == 0 {
+		return nil, errors.New("relationId is missing")
+	}
+	return v, nil
+}
+
+func TestUnknownType_Fail(t *testing.T) {
+	var a Attribute
+	err := json.Unmarshal([]byte(unknownTypesResult), &a)
+	assert.EqualError(t, err, "unknown type: janusgraph:RelationIdentifier")
+
+	err = StreamList(strings.NewReader(unknownTypesResult), func(Attribute) error { return nil })
+	assert.EqualError(t, err, "unknown type: janusgraph:RelationIdentifier")
+}
+
+func TestRegisterType(t *testing.T) {
+	require.NoError(t, RegisterType("janusgraph:RelationIdentifier", decodeRelationIdentifier))
+	defer UnregisterType("janusgraph:RelationIdentifier")
+	SetUnknownTypePolicy(UnknownTypeRaw)
+	defer SetUnknownTypePolicy(UnknownTypeFail)
+
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(unknownTypesResult), &a))
+	items := a.ListValue()
+	require.Len(t, items, 4)
+	assert.Equal(t, Attribute{Type: "janusgraph:RelationIdentifier", Value: relationIdentifier{RelationID: "4r6-39s-69zp-3c8"}}, items[1])
+
+	// registered types are written back with their type name
+	b, err := Marshal(items[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"@type":"janusgraph:RelationIdentifier","@value":{"relationId":"4r6-39s-69zp-3c8"}}`, string(b))
+
+	var wrong Attribute
+	err = json.Unmarshal([]byte(`{"@type":"janusgraph:RelationIdentifier","@value":{}}`), &wrong)
+	assert.EqualError(t, err, "wrong janusgraph:RelationIdentifier value: relationId is missing")
+
+	assert.Error(t, RegisterType("g:Int32", decodeRelationIdentifier))
+	assert.Error(t, RegisterType("", decodeRelationIdentifier))
+	assert.Error(t, RegisterType("janusgraph:Geoshape", nil))
+}
+
+func TestUnknownType_Raw(t *testing.T) {
+	SetUnknownTypePolicy(UnknownTypeRaw)
+	defer SetUnknownTypePolicy(UnknownTypeFail)
+
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(unknownTypesResult), &a))
+	items := a.ListValue()
+	require.Len(t, items, 4)
+	assert.Equal(t, DBType("janusgraph:RelationIdentifier"), items[1].Type)
+	assert.JSONEq(t, `{"relationId":"4r6-39s-69zp-3c8"}`, string(items[1].RawValue()))
+	location := items[2].MapValue().Get("location")
+	assert.Equal(t, DBType("janusgraph:Geoshape"), location.Type)
+	assert.JSONEq(t, `{"coordinates":[-97.7,30.2]}`, string(location.RawValue()))
+
+	var streamed List
+	err := StreamList(strings.NewReader(unknownTypesResult), func(a Attribute) error {
+		streamed = append(streamed, a)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, items, streamed)
+
+	// raw values are written back as they are read
+	b, err := Marshal(a)
+	require.NoError(t, err)
+	var decoded Attribute
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, a, decoded)
+}
+
+func TestUnknownType_Skip(t *testing.T) {
+	SetUnknownTypePolicy(UnknownTypeSkip)
+	defer SetUnknownTypePolicy(UnknownTypeFail)
+
+	expected := List{
+		{Type: TypeString, Value: "s1"},
+		{Type: TypeMap, Value: NewMap(MapEntry{Key: Attribute{Type: TypeString, Value: "sitter_id"}, Value: Attribute{Type: TypeString, Value: "s2"}})},
+		{Type: TypeProperty, Value: Property{Key: "location"}},
+	}
+	var a Attribute
+	require.NoError(t, json.Unmarshal([]byte(unknownTypesResult), &a))
+	assert.Equal(t, expected, a.ListValue())
+
+	var streamed List
+	err := StreamList(strings.NewReader(unknownTypesResult), func(a Attribute) error {
+		streamed = append(streamed, a)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, expected, streamed)
+
+	var m Attribute
+	require.NoError(t, json.Unmarshal([]byte(`{"sitter_id":"s1","location":{"@type":"janusgraph:Geoshape","@value":{}}}`), &m))
+	assert.Equal(t, NewMap(MapEntry{Key: Attribute{Type: TypeString, Value: "sitter_id"}, Value: Attribute{Type: TypeString, Value: "s1"}}), m.MapValue())
+}
+
+func TestParseUnknownTypePolicy(t *testing.T) {
+	for name, expected := range map[string]UnknownTypePolicy{"fail": UnknownTypeFail, "raw": UnknownTypeRaw, "skip": UnknownTypeSkip} {
+		p, err := ParseUnknownTypePolicy(name)
+		require.NoError(t, err)
+		assert.Equal(t, expected, p)
+	}
+	_, err := ParseUnknownTypePolicy("ignore")
+	assert.Error(t, err)
+}
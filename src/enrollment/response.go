@@ -0,0 +1,124 @@
+package enrollment
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	sortKeyName  = "sort_key"
+	distanceName = "distance"
+	zipName      = "zip"
+)
+
+var (
+	providerProfileKeys = []string{"gender", "avg_rank", "years_of_exp"}
+	serviceProfileKeys  = []string{"service", "min_rate", "max_rate"}
+)
+
+// ProviderResult is a provider profile with the service matched by the search
+type ProviderResult struct {
+	SitterID   string  `gremlin:"sitter_id"`
+	Gender     string  `gremlin:"gender"`
+	AvgRank    float64 `gremlin:"avg_rank"`
+	YearsOfExp int32   `gremlin:"years_of_exp"`
+	Service    string  `gremlin:"service"`
+	MinRate    float64 `gremlin:"min_rate"`
+	MaxRate    float64 `gremlin:"max_rate"`
+	ZIP        string  `gremlin:"zip"`
+	// Distance to the requested zip in miles, set for radius search only
+	Distance float64 `gremlin:"distance"`
+}
+
+type GRPCResponseModel struct {
+	SitterIDs []string
+	// Distances holds distance to the requested zip in miles by sitter_id, set for radius search only
+	Distances map[string]float64
+	// Providers holds found profiles in the same order as SitterIDs, set for ProjectProfiles only
+	Providers     []ProviderResult
+	NextPageToken string
+	// Total is the number of all found results on all pages.
+	// BuildResponse leaves it zero, Searcher sets it from BuildCountResponse of the BuildCountQuery result.
+	Total int64
+}
+
+// BuildResponse reads result of the query built by BuildQuery for the same request
+func BuildResponse(req *GRPCModel, recs [][]byte) (*GRPCResponseModel, error) {
+	if req == nil {
+		return nil, errors.New("empty request")
+	}
+	field, direction, err := getSort(req)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := UnmarshalList(recs)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := int(getPageSize(req))
+	hasNext := len(rows) > pageSize
+	if hasNext {
+		rows = rows[:pageSize]
+	}
+	res := &GRPCResponseModel{SitterIDs: make([]string, 0, len(rows))}
+	if isRadiusModel(req) {
+		res.Distances = make(map[string]float64, len(rows))
+	}
+	for _, row := range rows {
+		sitterID := rowSitterID(row).ToString()
+		res.SitterIDs = append(res.SitterIDs, sitterID)
+		if res.Distances != nil {
+			res.Distances[sitterID] = numberValue(row.MapValue().Get(distanceName))
+		}
+		if req.Projection == ProjectProfiles {
+			var provider ProviderResult
+			if err := Decode(row, &provider); err != nil {
+				return nil, err
+			}
+			res.Providers = append(res.Providers, provider)
+		}
+	}
+	if !hasNext || len(rows) == 0 {
+		return res, nil
+	}
+	res.NextPageToken, err = nextPageToken(rows[len(rows)-1], field, direction)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func nextPageToken(last Attribute, field SortField, direction SortDirection) (string, error) {
+	sitterID, err := newTokenValue(rowSitterID(last))
+	if err != nil {
+		return "", err
+	}
+	token := &pageToken{SitterID: sitterID}
+	if len(field) > 0 {
+		key, ok := last.MapValue().Lookup(sortKeyName)
+		if !ok {
+			return "", fmt.Errorf("%s is missing in result", sortKeyName)
+		}
+		tokenKey, err := newTokenValue(key)
+		if err != nil {
+			return "", err
+		}
+		token.Field = field
+		token.Direction = direction
+		token.Key = &tokenKey
+	}
+	return encodePageToken(token)
+}
+
+// rowSitterID returns sitter_id of the row: row is a map when sort key or distance is requested, otherwise it is sitter_id
+func rowSitterID(row Attribute) Attribute {
+	if row.Type == TypeMap {
+		return row.MapValue().Get("sitter_id")
+	}
+	return row
+}
+
+func numberValue(a Attribute) float64 {
+	f, _ := coerceFloat64(a)
+	return f
+}
@@ -0,0 +1,182 @@
+package enrollment
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildResponse(t *testing.T) {
+	req := &GRPCModel{CareType: "childCare", PageSize: 2}
+	recs := [][]byte{
+		[]byte(`{"@type":"g:List","@value":["s1","s2",{"@type":"g:String","@value":"s3"}]}`),
+	}
+
+	res, err := BuildResponse(req, recs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1", "s2"}, res.SitterIDs)
+	require.NotEmpty(t, res.NextPageToken)
+
+	token, err := decodePageToken(res.NextPageToken)
+	require.NoError(t, err)
+	assert.Equal(t, &pageToken{SitterID: tokenValue{Type: TypeString, Value: "s2"}}, token)
+}
+
+func TestBuildResponse_LastPage(t *testing.T) {
+	req := &GRPCModel{CareType: "childCare", PageSize: 2}
+	recs := [][]byte{
+		[]byte(`{"@type":"g:List","@value":["s1","s2"]}`),
+	}
+
+	res, err := BuildResponse(req, recs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1", "s2"}, res.SitterIDs)
+	assert.Empty(t, res.NextPageToken)
+}
+
+func TestBuildResponse_Sorted(t *testing.T) {
+	req := &GRPCModel{
+		CareType: "childCare",
+		Sort:     &SortGRPCModel{Field: SortByAvgRank, Direction: SortDesc},
+		PageSize: 1,
+	}
+	recs := [][]byte{
+		[]byte(`{"@type":"g:List","@value":[` +
+			`{"@type":"g:Map","@value":["sitter_id",{"@type":"g:Int32","@value":7},"sort_key",{"@type":"g:Float","@value":4.7}]},` +
+			`{"@type":"g:Map","@value":["sitter_id",{"@type":"g:Int32","@value":8},"sort_key",{"@type":"g:Float","@value":4.5}]}]}`),
+	}
+
+	res, err := BuildResponse(req, recs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"7"}, res.SitterIDs)
+
+	token, err := decodePageToken(res.NextPageToken)
+	require.NoError(t, err)
+	assert.Equal(t, &pageToken{
+		Field:     SortByAvgRank,
+		Direction: SortDesc,
+		Key:       &tokenValue{Type: TypeFloat, Value: "4.7"},
+		SitterID:  tokenValue{Type: TypeInteger, Value: "7"},
+	}, token)
+
+	req.PageToken = res.NextPageToken
+	_, _, err = BuildQuery(req)
+	assert.NoError(t, err)
+}
+
+func TestPageToken_Tampered(t *testing.T) {
+	token, err := encodePageToken(&pageToken{SitterID: tokenValue{Type: TypeString, Value: "s1"}})
+	require.NoError(t, err)
+
+	secret := pageTokenSecret
+	require.NoError(t, SetPageTokenSecret([]byte("another secret")))
+	defer SetPageTokenSecret(secret)
+
+	_, err = decodePageToken(token)
+	assert.Equal(t, ErrInvalidPageToken, err)
+}
+
+func TestPageToken_NoSecret(t *testing.T) {
+	secret := pageTokenSecret
+	defer SetPageTokenSecret(secret)
+	assert.Equal(t, ErrNoPageTokenSecret, SetPageTokenSecret(nil))
+	pageTokenSecret = nil
+
+	_, err := encodePageToken(&pageToken{SitterID: tokenValue{Type: TypeString, Value: "s1"}})
+	assert.Equal(t, ErrNoPageTokenSecret, err)
+	_, err = decodePageToken("e30.e30")
+	assert.Equal(t, ErrNoPageTokenSecret, err)
+
+	// a missing secret is not a wrong request
+	err = Validate(&GRPCModel{PageToken: "e30.e30"}, DefaultMaxPageSize)
+	assert.Equal(t, ErrNoPageTokenSecret, err)
+}
+
+func TestPageToken_FloatNumber(t *testing.T) {
+	for value, expected := range map[tokenValue]string{
+		{Type: TypeDouble, Value: "4.5"}:       "4.5",
+		{Type: TypeDouble, Value: "4.50"}:      "4.5",
+		{Type: TypeDouble, Value: "1e21"}:      "1e+21",
+		{Type: TypeFloat, Value: "0.1"}:        "0.1",
+		{Type: TypeFloat, Value: "-2"}:         "-2",
+		{Type: TypeDouble, Value: "-1.25e-07"}: "-1.25e-07",
+	} {
+		n, err := value.number()
+		assert.NoError(t, err, value.Value)
+		assert.Equal(t, expected, n)
+	}
+	for _, v := range []string{"NaN", "nan", "Inf", "+Inf", "-Infinity", "0x1p-2", "0X1.8P1", "1e400", "4.5d", ""} {
+		_, err := tokenValue{Type: TypeDouble, Value: v}.number()
+		assert.Equal(t, ErrInvalidPageToken, err, v)
+		_, err = tokenValue{Type: TypeFloat, Value: v}.number()
+		assert.Equal(t, ErrInvalidPageToken, err, v)
+	}
+
+	// strings are bound as they are
+	_, err := tokenValue{Type: TypeString, Value: "s1"}.number()
+	assert.Equal(t, ErrInvalidPageToken, err)
+
+	_, err = newTokenValue(Attribute{Type: TypeDouble, Value: math.NaN()})
+	assert.Error(t, err)
+	_, err = newTokenValue(Attribute{Type: TypeFloat, Value: math.Inf(1)})
+	assert.Error(t, err)
+}
+
+func TestBuildResponse_Radius(t *testing.T) {
+	req := &GRPCModel{PostalCode: "78704", MaxDistance: 5}
+	recs := [][]byte{
+		[]byte(`{"@type":"g:List","@value":[` +
+			`{"@type":"g:Map","@value":["sitter_id","s1","distance",{"@type":"g:Int32","@value":0}]},` +
+			`{"@type":"g:Map","@value":["sitter_id","s2","distance",{"@type":"g:Double","@value":2.5}]}]}`),
+	}
+
+	res, err := BuildResponse(req, recs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1", "s2"}, res.SitterIDs)
+	assert.Equal(t, map[string]float64{"s1": 0, "s2": 2.5}, res.Distances)
+	assert.Empty(t, res.NextPageToken)
+}
+
+func TestBuildResponse_Profiles(t *testing.T) {
+	req := &GRPCModel{CareType: "petCare", Projection: ProjectProfiles}
+	recs := [][]byte{
+		[]byte(`{"@type":"g:List","@value":[{"@type":"g:Map","@value":[` +
+			`"sitter_id","s1",` +
+			`"gender",{"@type":"g:List","@value":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int32","@value":1},"label":"gender","value":"female"}}]},` +
+			`"avg_rank",{"@type":"g:List","@value":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int32","@value":2},"label":"avg_rank","value":{"@type":"g:Float","@value":4.5}}}]},` +
+			`"years_of_exp",{"@type":"g:List","@value":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int32","@value":3},"label":"years_of_exp","value":{"@type":"g:Int32","@value":10}}}]},` +
+			`"service",{"@type":"g:List","@value":[{"@type":"g:Property","@value":{"key":"service","value":"petCare"}}]},` +
+			`"min_rate",{"@type":"g:List","@value":[{"@type":"g:Property","@value":{"key":"min_rate","value":{"@type":"g:Int32","@value":5}}}]},` +
+			`"max_rate",{"@type":"g:List","@value":[{"@type":"g:Property","@value":{"key":"max_rate","value":{"@type":"g:Int32","@value":40}}}]},` +
+			`"zip",{"@type":"g:List","@value":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int32","@value":4},"label":"name","value":"78704"}}]}` +
+			`]}]}`),
+	}
+
+	res, err := BuildResponse(req, recs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1"}, res.SitterIDs)
+	assert.Equal(t, []ProviderResult{{
+		SitterID:   "s1",
+		Gender:     "female",
+		AvgRank:    4.5,
+		YearsOfExp: 10,
+		Service:    "petCare",
+		MinRate:    5,
+		MaxRate:    40,
+		ZIP:        "78704",
+	}}, res.Providers)
+}
+
+func TestBuildResponse_ProfilesMissingProperties(t *testing.T) {
+	req := &GRPCModel{CareType: "petCare", Projection: ProjectProfiles}
+	recs := [][]byte{
+		[]byte(`{"@type":"g:List","@value":[{"@type":"g:Map","@value":[` +
+			`"sitter_id","s1","gender",{"@type":"g:List","@value":[]},"zip",{"@type":"g:List","@value":[]}]}]}`),
+	}
+
+	res, err := BuildResponse(req, recs)
+	require.NoError(t, err)
+	assert.Equal(t, []ProviderResult{{SitterID: "s1"}}, res.Providers)
+}
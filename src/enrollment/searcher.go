@@ -0,0 +1,167 @@
+package enrollment
+
+import (
+	"context"
+
+	"github.com/northwesternmutual/grammes/manager"
+	"github.com/northwesternmutual/grammes/query"
+)
+
+// Searcher finds providers matching the request.
+// Errors are ValidationErrors for a wrong request, *BuildError when the query cannot be built from the request,
+// e.g. wrapping ErrPageTokenMismatch, *QueryError when the graph fails to run a query,
+// *ResultError when a result cannot be read, and the context error when ctx is done first.
+type Searcher interface {
+	// Search returns a page of found providers with Total set
+	Search(ctx context.Context, req *GRPCModel) (*GRPCResponseModel, error)
+}
+
+// BuildError is a request which passed Validate but cannot be built into a query
+type BuildError struct {
+	Err error
+}
+
+func (e *BuildError) Error() string {
+	return "cannot build query: " + e.Err.Error()
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// QueryError is a failure to run a query on the graph, e.g. a connection or a server error
+type QueryError struct {
+	Err error
+}
+
+func (e *QueryError) Error() string {
+	return "query failed: " + e.Err.Error()
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// ResultError is a query result which cannot be read
+type ResultError struct {
+	Err error
+}
+
+func (e *ResultError) Error() string {
+	return "wrong query result: " + e.Err.Error()
+}
+
+func (e *ResultError) Unwrap() error {
+	return e.Err
+}
+
+type searcher struct {
+	client      manager.ExecuteQuerier
+	maxPageSize int32
+	version     GraphSONVersion
+}
+
+// SearcherOption configures Searcher returned by NewSearcher
+type SearcherOption func(*searcher)
+
+// WithGraphSONVersion makes Searcher fail query results which are not written in the version with *ResultError,
+// see CheckGraphSONVersion. Results of any version are read by default.
+func WithGraphSONVersion(version GraphSONVersion) SearcherOption {
+	return func(s *searcher) {
+		s.version = version
+	}
+}
+
+// NewSearcher returns Searcher running queries with the client, e.g. *grammes.Client.
+// Page size of requests is clamped to maxPageSize, see Validate, a non-positive maxPageSize is DefaultMaxPageSize.
+func NewSearcher(client manager.ExecuteQuerier, maxPageSize int32, opts ...SearcherOption) Searcher {
+	if maxPageSize <= 0 {
+		maxPageSize = DefaultMaxPageSize
+	}
+	s := &searcher{client: client, maxPageSize: maxPageSize}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Search runs the page query built by BuildQuery and the count query built by BuildCountQuery.
+// The client does not support cancellation, so when ctx is done a running query is left to finish in background.
+func (s *searcher) Search(ctx context.Context, req *GRPCModel) (*GRPCResponseModel, error) {
+	if req == nil {
+		return nil, errEmptyRequest
+	}
+	// do not clamp the caller's request
+	r := *req
+	if err := Validate(&r, s.maxPageSize); err != nil {
+		return nil, err
+	}
+	q, bindings, err := BuildQuery(&r)
+	if err != nil {
+		return nil, &BuildError{Err: err}
+	}
+	countQ, countBindings, err := BuildCountQuery(&r)
+	if err != nil {
+		return nil, &BuildError{Err: err}
+	}
+
+	recs, err := s.execute(ctx, q, bindings)
+	if err != nil {
+		return nil, err
+	}
+	page, err := BuildResponse(&r, recs)
+	if err != nil {
+		return nil, &ResultError{Err: err}
+	}
+
+	recs, err = s.execute(ctx, countQ, countBindings)
+	if err != nil {
+		return nil, err
+	}
+	page.Total, err = BuildCountResponse(recs)
+	if err != nil {
+		return nil, &ResultError{Err: err}
+	}
+
+	return page, nil
+}
+
+type executeResult struct {
+	recs [][]byte
+	err  error
+}
+
+func (s *searcher) execute(ctx context.Context, q query.Query, bindings Bindings) ([][]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	done := make(chan executeResult, 1)
+	go func() {
+		recs, err := s.client.ExecuteBoundQuery(q, bindings, nil)
+		done <- executeResult{recs: recs, err: err}
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-done:
+		if res.err != nil {
+			return nil, &QueryError{Err: res.err}
+		}
+		if err := s.checkVersion(res.recs); err != nil {
+			return nil, &ResultError{Err: err}
+		}
+		return res.recs, nil
+	}
+}
+
+func (s *searcher) checkVersion(recs [][]byte) error {
+	for _, r := range recs {
+		if isNullValue(r) {
+			continue
+		}
+		if err := CheckGraphSONVersion(r, s.version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package enrollment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akhripko/gremlin-grammes/src/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearcher_Search(t *testing.T) {
+	client := &testutil.Querier{
+		Page:  `{"@type":"g:List","@value":["s1","s2","s3"]}`,
+		Count: `{"@type":"g:List","@value":[{"@type":"g:Int64","@value":7}]}`,
+	}
+	req := &GRPCModel{CareType: "childCare", PageSize: 50}
+	res, err := NewSearcher(client, 2).Search(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1", "s2"}, res.SitterIDs)
+	assert.NotEmpty(t, res.NextPageToken)
+	assert.Equal(t, int64(7), res.Total)
+	assert.Len(t, client.Queries(), 2)
+	// request of the caller is not changed
+	assert.Equal(t, int32(50), req.PageSize)
+}
+
+func TestSearcher_Search_DefaultMaxPageSize(t *testing.T) {
+	client := &testutil.Querier{
+		Page:  `{"@type":"g:List","@value":[]}`,
+		Count: `{"@type":"g:List","@value":[{"@type":"g:Int64","@value":0}]}`,
+	}
+	_, err := NewSearcher(client, 0).Search(context.Background(), &GRPCModel{CareType: "childCare", PageSize: 1000})
+	require.NoError(t, err)
+	assert.Contains(t, client.Queries()[0], ".limit(101)")
+}
+
+func TestSearcher_Search_Errors(t *testing.T) {
+	ok := `{"@type":"g:List","@value":[]}`
+
+	_, err := NewSearcher(&testutil.Querier{}, 10).Search(context.Background(), &GRPCModel{CareType: "unknown"})
+	var validation ValidationErrors
+	assert.True(t, errors.As(err, &validation))
+
+	token, err := encodePageToken(&pageToken{SitterID: tokenValue{Type: TypeString, Value: "s1"}})
+	require.NoError(t, err)
+	sorted := &GRPCModel{CareType: "childCare", Sort: &SortGRPCModel{Field: SortByAvgRank}, PageToken: token}
+	_, err = NewSearcher(&testutil.Querier{}, 10).Search(context.Background(), sorted)
+	var buildErr *BuildError
+	require.True(t, errors.As(err, &buildErr))
+	assert.True(t, errors.Is(err, ErrPageTokenMismatch))
+
+	_, err = NewSearcher(&testutil.Querier{}, 10).Search(context.Background(), nil)
+	assert.True(t, errors.As(err, &validation))
+
+	_, err = NewSearcher(&testutil.Querier{Err: errors.New("connection lost")}, 10).Search(context.Background(), &GRPCModel{CareType: "childCare"})
+	var queryErr *QueryError
+	require.True(t, errors.As(err, &queryErr))
+	assert.EqualError(t, err, "query failed: connection lost")
+
+	_, err = NewSearcher(&testutil.Querier{Page: ok, Count: `{"@type":"g:List","@value":["7"]}`}, 10).Search(context.Background(), &GRPCModel{CareType: "childCare"})
+	var resultErr *ResultError
+	require.True(t, errors.As(err, &resultErr))
+	assert.EqualError(t, err, "wrong query result: got g:String where g:Long is expected")
+}
+
+func TestSearcher_Search_GraphSONVersion(t *testing.T) {
+	client := &testutil.Querier{
+		Page:  `["s1","s2"]`,
+		Count: `{"@type":"g:List","@value":[{"@type":"g:Int64","@value":2}]}`,
+	}
+	req := &GRPCModel{CareType: "childCare"}
+	res, err := NewSearcher(client, 10).Search(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1", "s2"}, res.SitterIDs)
+
+	_, err = NewSearcher(client, 10, WithGraphSONVersion(GraphSONV3)).Search(context.Background(), req)
+	var resultErr *ResultError
+	require.True(t, errors.As(err, &resultErr))
+	assert.EqualError(t, err, "wrong query result: untyped array is not allowed in GraphSON 3")
+}
+
+func TestSearcher_Search_Context(t *testing.T) {
+	client := &testutil.Querier{Block: make(chan struct{})}
+	defer close(client.Block)
+	ctx, cancel := context.WithCancel(context.Background())
+	go cancel()
+	_, err := NewSearcher(client, 10).Search(ctx, &GRPCModel{CareType: "childCare"})
+	assert.Equal(t, context.Canceled, err)
+}
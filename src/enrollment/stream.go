@@ -0,0 +1,257 @@
+package enrollment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// StreamList reads items of one g:List record from r and calls fn for every item as it is read,
+// so the whole list is never kept in memory. A plain json array of GraphSON 1 and 2 is read as well.
+// The list must have "@type" before "@value", as GraphSON writers do.
+// An error returned by fn stops reading and is returned as is.
+func StreamList(r io.Reader, fn func(Attribute) error) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case nil:
+		return nil
+	case json.Delim('['):
+		return streamItems(dec, fn)
+	case json.Delim('{'):
+	default:
+		return fmt.Errorf("got %v where %s is expected", tok, TypeList)
+	}
+	if err := expectKey(dec, "@type"); err != nil {
+		return err
+	}
+	var t string
+	if err := dec.Decode(&t); err != nil {
+		return err
+	}
+	if dbTypes[t] != TypeList {
+		return fmt.Errorf("got %s where %s is expected", t, TypeList)
+	}
+	if err := expectKey(dec, "@value"); err != nil {
+		return err
+	}
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	if err := streamItems(dec, fn); err != nil {
+		return err
+	}
+	return expectDelim(dec, '}')
+}
+
+// StreamRecords calls StreamList for every record, null records are skipped
+func StreamRecords(recs [][]byte, fn func(Attribute) error) error {
+	return StreamRecordsVersion(recs, GraphSONAuto, fn)
+}
+
+// StreamRecordsVersion is StreamRecords failing on records which are not written in the version,
+// see CheckGraphSONVersion
+func StreamRecordsVersion(recs [][]byte, version GraphSONVersion, fn func(Attribute) error) error {
+	for _, r := range recs {
+		if isNullValue(r) {
+			continue
+		}
+		if err := CheckGraphSONVersion(r, version); err != nil {
+			return err
+		}
+		if err := StreamList(bytes.NewReader(r), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamItems reads array items up to the closing bracket
+func streamItems(dec *json.Decoder, fn func(Attribute) error) error {
+	for dec.More() {
+		a, err := readAttribute(dec)
+		if err == errSkipped {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(a); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// readAttribute reads the next value of dec the same way Attribute.UnmarshalJSON reads it,
+// errSkipped is returned for a value dropped by UnknownTypeSkip
+func readAttribute(dec *json.Decoder) (Attribute, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return Attribute{}, err
+	}
+	switch v := tok.(type) {
+	case nil:
+		return Attribute{}, nil
+	case string:
+		return Attribute{Type: TypeString, Value: v}, nil
+	case bool:
+		return Attribute{Type: TypeBoolean, Value: v}, nil
+	case json.Number:
+		return numberAttribute(v)
+	case json.Delim:
+		if v == '[' {
+			var l List
+			err := streamItems(dec, func(a Attribute) error {
+				l = append(l, a)
+				return nil
+			})
+			return Attribute{Type: TypeList, Value: l}, err
+		}
+		return readObject(dec)
+	}
+	return Attribute{}, fmt.Errorf("unexpected token: %v", tok)
+}
+
+// readObject reads an object after its opening brace: a typed value or an untyped map
+func readObject(dec *json.Decoder) (Attribute, error) {
+	var rec dbRecord
+	var typed bool
+	m := Map{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return Attribute{}, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return Attribute{}, fmt.Errorf("wrong object key: %v", tok)
+		}
+		switch {
+		case key == "@type" && m.Len() == 0:
+			typed = true
+			err = dec.Decode(&rec.Type)
+		case key == "@value" && m.Len() == 0:
+			if typed {
+				if a, ok, err := readScalar(dec, dbTypes[rec.Type]); ok || err != nil {
+					if err == nil {
+						err = expectDelim(dec, '}')
+					}
+					return a, err
+				}
+			}
+			// the value is decoded by its type, which can be read after it
+			err = dec.Decode(&rec.Raw)
+		default:
+			if typed {
+				return Attribute{}, fmt.Errorf("unexpected key of %s: %s", rec.Type, key)
+			}
+			var value Attribute
+			value, err = readAttribute(dec)
+			if err == nil {
+				m.add(Attribute{Type: TypeString, Value: key}, value)
+			} else if err == errSkipped {
+				err = nil
+			}
+		}
+		if err != nil {
+			return Attribute{}, err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return Attribute{}, err
+	}
+	if typed {
+		var a Attribute
+		err := a.unmarshalByType(&rec)
+		return a, err
+	}
+	if rec.Raw != nil {
+		return Attribute{}, errors.New("@value without @type")
+	}
+	if isElementMap(m) {
+		// GraphSON 1 vertex and edge are rare, they are read from the map written back
+		b, err := MarshalVersion(m, GraphSONV2)
+		if err != nil {
+			return Attribute{}, err
+		}
+		if m.Get("type").StringValue() == "vertex" {
+			var v Vertex
+			err = json.Unmarshal(b, &v)
+			return Attribute{Type: TypeVertex, Value: v}, err
+		}
+		var e Edge
+		err = json.Unmarshal(b, &e)
+		return Attribute{Type: TypeEdge, Value: e}, err
+	}
+	return Attribute{Type: TypeMap, Value: m}, nil
+}
+
+// readScalar reads numbers and strings straight from the token, other types are not read
+func readScalar(dec *json.Decoder, t DBType) (Attribute, bool, error) {
+	switch t {
+	case TypeInteger, TypeLong, TypeString:
+	default:
+		return Attribute{}, false, nil
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return Attribute{}, true, err
+	}
+	switch v := tok.(type) {
+	case string:
+		if t == TypeString {
+			return Attribute{Type: TypeString, Value: v}, true, nil
+		}
+	case json.Number:
+		switch t {
+		case TypeInteger:
+			n, err := strconv.ParseInt(v.String(), 10, 32)
+			return Attribute{Type: TypeInteger, Value: int32(n)}, true, err
+		case TypeLong:
+			n, err := strconv.ParseInt(v.String(), 10, 64)
+			return Attribute{Type: TypeLong, Value: n}, true, err
+		}
+	}
+	return Attribute{}, true, fmt.Errorf("wrong %s value: %v", t, tok)
+}
+
+func isElementMap(m Map) bool {
+	if _, ok := m.Lookup("id"); !ok {
+		return false
+	}
+	switch m.Get("type").StringValue() {
+	case "vertex", "edge":
+		return true
+	}
+	return false
+}
+
+func expectKey(dec *json.Decoder, key string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != key {
+		return fmt.Errorf("got %v where %s is expected", tok, key)
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("got %v where %v is expected", tok, delim)
+	}
+	return nil
+}
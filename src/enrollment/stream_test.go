@@ -0,0 +1,180 @@
+package enrollment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamList(t *testing.T) {
+	js := `{"@type":"g:List","@value":["s1",{"@type":"g:Int64","@value":2},{"@value":1.5,"@type":"g:Double"},true,null,` +
+		`{"@type":"g:Map","@value":[{"@type":"g:T","@value":"id"},{"@type":"g:Int32","@value":1},"service",{"@type":"g:List","@value":["childCare"]}]},` +
+		`{"b":[1,2.5],"a":"x"},` +
+		`{"id":1,"label":"provider","type":"vertex","properties":{"sitter_id":[{"id":10,"value":"s1"}]}}]}`
+	var expected Attribute
+	require.NoError(t, json.Unmarshal([]byte(js), &expected))
+
+	var items List
+	err := StreamList(strings.NewReader(js), func(a Attribute) error {
+		items = append(items, a)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, expected.ListValue(), items)
+}
+
+func TestStreamRecords(t *testing.T) {
+	recs := [][]byte{
+		[]byte(`{"@type":"g:List","@value":["s1","s2"]}`),
+		[]byte(`null`),
+		[]byte(`["s3"]`),
+	}
+	var ids []string
+	err := StreamRecords(recs, func(a Attribute) error {
+		ids = append(ids, a.StringValue())
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1", "s2", "s3"}, ids)
+
+	stop := errors.New("stop")
+	var n int
+	err = StreamRecords(recs, func(a Attribute) error {
+		n++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestStreamList_Errors(t *testing.T) {
+	tests := []string{
+		`{"@type":"g:Map","@value":[]}`,
+		`{"@value":[],"@type":"g:List"}`,
+		`"s1"`,
+		`{"@type":"g:List","@value":[{"@type":"g:Int32","@value":"x"}]}`,
+		`{"@type":"g:List","@value":[{"@type":"g:Int32","@value":1,"x":2}]}`,
+		`{"@type":"g:List","@value":["s1"`,
+	}
+	for _, js := range tests {
+		err := StreamList(strings.NewReader(js), func(Attribute) error { return nil })
+		assert.Error(t, err, js)
+	}
+}
+
+func benchmarkRecords(item func(i int) string) [][]byte {
+	var recs [][]byte
+	for r := 0; r < 10; r++ {
+		var buf bytes.Buffer
+		buf.WriteString(`{"@type":"g:List","@value":[`)
+		for i := 0; i < 1000; i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(item(r*1000 + i))
+		}
+		buf.WriteString(`]}`)
+		recs = append(recs, buf.Bytes())
+	}
+	return recs
+}
+
+func stringRecords() [][]byte {
+	return benchmarkRecords(func(i int) string {
+		return `"sitter-` + strconv.Itoa(i) + `"`
+	})
+}
+
+func int32Records() [][]byte {
+	return benchmarkRecords(func(i int) string {
+		return `{"@type":"g:Int32","@value":` + strconv.Itoa(i) + `}`
+	})
+}
+
+// treeStringList is the former UnmarshalStringList, which decodes every record into List first
+func treeStringList(recs [][]byte) ([]string, error) {
+	var items []string
+	var list ListOfStrings
+	for _, r := range recs {
+		if err := json.Unmarshal(r, &list); err != nil {
+			return nil, err
+		}
+		items = append(items, list...)
+	}
+	return items, nil
+}
+
+// treeInt32List is the former UnmarshalInt32List
+func treeInt32List(recs [][]byte) ([]int32, error) {
+	var items []int32
+	var list ListOfInt32
+	for _, r := range recs {
+		if err := json.Unmarshal(r, &list); err != nil {
+			return nil, err
+		}
+		items = append(items, list...)
+	}
+	return items, nil
+}
+
+func TestUnmarshalStringList_SameAsTree(t *testing.T) {
+	recs := stringRecords()
+	expected, err := treeStringList(recs)
+	require.NoError(t, err)
+	ids, err := UnmarshalStringList(recs)
+	require.NoError(t, err)
+	assert.Equal(t, expected, ids)
+
+	recs = int32Records()
+	expectedInt32, err := treeInt32List(recs)
+	require.NoError(t, err)
+	idsInt32, err := UnmarshalInt32List(recs)
+	require.NoError(t, err)
+	assert.Equal(t, expectedInt32, idsInt32)
+}
+
+func BenchmarkUnmarshalStringList_Tree(b *testing.B) {
+	recs := stringRecords()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := treeStringList(recs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalStringList_Stream(b *testing.B) {
+	recs := stringRecords()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalStringList(recs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalInt32List_Tree(b *testing.B) {
+	recs := int32Records()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := treeInt32List(recs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalInt32List_Stream(b *testing.B) {
+	recs := int32Records()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalInt32List(recs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,8 +1,14 @@
 package enrollment
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
 )
 
 type DBType string
@@ -20,17 +26,87 @@ const (
 	TypeLong           DBType = "g:Long"
 	TypeDate           DBType = "g:Date"
 	TypeDouble         DBType = "g:Double"
+	TypeUUID           DBType = "g:UUID"
+	TypeClass          DBType = "g:Class"
+	TypeT              DBType = "g:T"
+	TypeDirection      DBType = "g:Direction"
+	TypeVertex         DBType = "g:Vertex"
+	TypeEdge           DBType = "g:Edge"
+	TypePath           DBType = "g:Path"
+	TypeSet            DBType = "g:Set"
+	TypeBulkSet        DBType = "g:BulkSet"
+	TypeTree           DBType = "g:Tree"
+	TypeTraverser      DBType = "g:Traverser"
+
+	// gx: types of the GraphSON extended module
+	TypeBigDecimal     DBType = "gx:BigDecimal"
+	TypeBigInteger     DBType = "gx:BigInteger"
+	TypeByte           DBType = "gx:Byte"
+	TypeByteBuffer     DBType = "gx:ByteBuffer"
+	TypeChar           DBType = "gx:Char"
+	TypeInt16          DBType = "gx:Int16"
+	TypeDuration       DBType = "gx:Duration"
+	TypeInstant        DBType = "gx:Instant"
+	TypeLocalDate      DBType = "gx:LocalDate"
+	TypeLocalDateTime  DBType = "gx:LocalDateTime"
+	TypeOffsetDateTime DBType = "gx:OffsetDateTime"
+)
+
+// T is a token of an element: g:T
+type T string
+
+const (
+	TID    T = "id"
+	TLabel T = "label"
+	TKey   T = "key"
+	TValue T = "value"
+)
+
+// Direction is an edge direction: g:Direction
+type Direction string
+
+const (
+	DirectionOut  Direction = "OUT"
+	DirectionIn   Direction = "IN"
+	DirectionBoth Direction = "BOTH"
 )
 
 var dbTypes = map[string]DBType{
-	"g:String":         TypeString,
-	"g:Int32":          TypeInteger,
-	"g:Boolean":        TypeBoolean,
-	"g:List":           TypeList,
-	"g:Map":            TypeMap,
-	"g:Float":          TypeFloat,
-	"g:VertexProperty": TypeVertexProperty,
-	"g:Property":       TypeProperty,
+	"g:String":          TypeString,
+	"g:Int32":           TypeInteger,
+	"g:Int64":           TypeLong,
+	"g:Long":            TypeLong,
+	"g:Boolean":         TypeBoolean,
+	"g:List":            TypeList,
+	"g:Map":             TypeMap,
+	"g:Float":           TypeFloat,
+	"g:Double":          TypeDouble,
+	"g:Date":            TypeDate,
+	"g:Timestamp":       TypeTimestamp,
+	"g:UUID":            TypeUUID,
+	"g:Class":           TypeClass,
+	"g:T":               TypeT,
+	"g:Direction":       TypeDirection,
+	"g:VertexProperty":  TypeVertexProperty,
+	"g:Property":        TypeProperty,
+	"g:Vertex":          TypeVertex,
+	"g:Edge":            TypeEdge,
+	"g:Path":            TypePath,
+	"g:Set":             TypeSet,
+	"g:BulkSet":         TypeBulkSet,
+	"g:Tree":            TypeTree,
+	"g:Traverser":       TypeTraverser,
+	"gx:BigDecimal":     TypeBigDecimal,
+	"gx:BigInteger":     TypeBigInteger,
+	"gx:Byte":           TypeByte,
+	"gx:ByteBuffer":     TypeByteBuffer,
+	"gx:Char":           TypeChar,
+	"gx:Int16":          TypeInt16,
+	"gx:Duration":       TypeDuration,
+	"gx:Instant":        TypeInstant,
+	"gx:LocalDate":      TypeLocalDate,
+	"gx:LocalDateTime":  TypeLocalDateTime,
+	"gx:OffsetDateTime": TypeOffsetDateTime,
 }
 
 type unmarshal func(raw []byte, v *interface{}) error
@@ -38,12 +114,38 @@ type unmarshal func(raw []byte, v *interface{}) error
 var dbUnmarshals = map[DBType]unmarshal{
 	TypeString:         toString,
 	TypeInteger:        toInt32,
+	TypeLong:           toInt64,
 	TypeFloat:          toFloat64,
+	TypeDouble:         toFloat64,
+	TypeDate:           toTime,
+	TypeTimestamp:      toTime,
+	TypeUUID:           toUUID,
+	TypeClass:          toString,
+	TypeT:              toT,
+	TypeDirection:      toDirection,
 	TypeBoolean:        toBool,
 	TypeList:           toList,
 	TypeMap:            toMap,
 	TypeVertexProperty: toVertexProperty,
 	TypeProperty:       toProperty,
+	TypeVertex:         toVertex,
+	TypeEdge:           toEdge,
+	TypePath:           toPath,
+	TypeSet:            toList,
+	TypeBulkSet:        toBulkSet,
+	TypeTree:           toTree,
+	TypeTraverser:      toTraverser,
+	TypeBigDecimal:     toDecimal,
+	TypeBigInteger:     toBigInt,
+	TypeByte:           toByte,
+	TypeByteBuffer:     toByteBuffer,
+	TypeChar:           toChar,
+	TypeInt16:          toInt16,
+	TypeDuration:       toDuration,
+	TypeInstant:        toInstant,
+	TypeLocalDate:      toLocalDate,
+	TypeLocalDateTime:  toLocalDateTime,
+	TypeOffsetDateTime: toOffsetDateTime,
 }
 
 func toString(raw []byte, v *interface{}) error {
@@ -64,15 +166,90 @@ func toInt32(raw []byte, v *interface{}) error {
 	return nil
 }
 
+func toInt64(raw []byte, v *interface{}) error {
+	var val int64
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	*v = val
+	return nil
+}
+
 func toFloat64(raw []byte, v *interface{}) error {
 	var val float64
-	if err := json.Unmarshal(raw, &val); err != nil {
+	if err := json.Unmarshal(raw, &val); err == nil {
+		*v = val
+		return nil
+	}
+	// NaN and infinities are written as strings
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
 		return err
 	}
+	switch s {
+	case "NaN":
+		val = math.NaN()
+	case "Infinity":
+		val = math.Inf(1)
+	case "-Infinity":
+		val = math.Inf(-1)
+	default:
+		return fmt.Errorf("wrong floating point value: %s", s)
+	}
 	*v = val
 	return nil
 }
 
+// toTime reads milliseconds since epoch
+func toTime(raw []byte, v *interface{}) error {
+	var val int64
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	*v = time.Unix(val/1000, (val%1000)*int64(time.Millisecond)).UTC()
+	return nil
+}
+
+func toUUID(raw []byte, v *interface{}) error {
+	var val string
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	if !uuidPattern.MatchString(val) {
+		return fmt.Errorf("wrong %s value: %s", TypeUUID, val)
+	}
+	*v = strings.ToLower(val)
+	return nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func toT(raw []byte, v *interface{}) error {
+	var val string
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	switch T(val) {
+	case TID, TLabel, TKey, TValue:
+		*v = T(val)
+		return nil
+	}
+	return fmt.Errorf("wrong %s value: %s", TypeT, val)
+}
+
+func toDirection(raw []byte, v *interface{}) error {
+	var val string
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return err
+	}
+	switch Direction(val) {
+	case DirectionOut, DirectionIn, DirectionBoth:
+		*v = Direction(val)
+		return nil
+	}
+	return fmt.Errorf("wrong %s value: %s", TypeDirection, val)
+}
+
 func toBool(raw []byte, v *interface{}) error {
 	var val bool
 	if err := json.Unmarshal(raw, &val); err != nil {
@@ -136,19 +313,16 @@ func (l *ListOfStrings) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// UnmarshalStringList joins g:List records into one list of string forms of the items.
+// Items are streamed, so records are not decoded into List first.
 func UnmarshalStringList(recs [][]byte) ([]string, error) {
-	var err error
 	var items []string
-	var list ListOfStrings
-	for _, r := range recs {
-		if isNullValue(r) {
-			continue
-		}
-		err = json.Unmarshal(r, &list)
-		if err != nil {
-			return nil, err
-		}
-		items = append(items, list...)
+	err := StreamRecords(recs, func(a Attribute) error {
+		items = append(items, a.ToString())
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return items, nil
 }
@@ -171,19 +345,43 @@ func (l *ListOfInt32) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// UnmarshalInt32List joins g:List records of g:Int32 into one list, items are streamed as in UnmarshalStringList
 func UnmarshalInt32List(recs [][]byte) ([]int32, error) {
-	var err error
 	var items []int32
-	var list ListOfInt32
+	err := StreamRecords(recs, func(a Attribute) error {
+		items = append(items, a.Int32Value())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UnmarshalList joins g:List records into one list
+func UnmarshalList(recs [][]byte) (List, error) {
+	return UnmarshalListVersion(recs, GraphSONAuto)
+}
+
+// UnmarshalListVersion is UnmarshalList failing on records which are not written in the version,
+// see CheckGraphSONVersion
+func UnmarshalListVersion(recs [][]byte, version GraphSONVersion) (List, error) {
+	var items List
 	for _, r := range recs {
 		if isNullValue(r) {
 			continue
 		}
-		err = json.Unmarshal(r, &list)
-		if err != nil {
+		if err := CheckGraphSONVersion(r, version); err != nil {
 			return nil, err
 		}
-		items = append(items, list...)
+		var a Attribute
+		if err := json.Unmarshal(r, &a); err != nil {
+			return nil, err
+		}
+		if a.Type != TypeList {
+			return nil, fmt.Errorf("got %s where %s is expected", a.Type, TypeList)
+		}
+		items = append(items, a.ListValue()...)
 	}
 	return items, nil
 }
@@ -195,7 +393,156 @@ type dbRecord struct {
 
 type List []Attribute
 
-type Map map[string]Attribute
+// UnmarshalJSON reads json array of values, values dropped by UnknownTypeSkip are not added
+func (l *List) UnmarshalJSON(b []byte) error {
+	if unknownTypePolicy() != UnknownTypeSkip {
+		return json.Unmarshal(b, (*[]Attribute)(l))
+	}
+	if isNullValue(bytes.TrimSpace(b)) {
+		*l = nil
+		return nil
+	}
+	*l = List{}
+	return unmarshalItems(b, func(a Attribute, skipped bool) {
+		if !skipped {
+			*l = append(*l, a)
+		}
+	})
+}
+
+// Map keeps g:Map entries with typed keys in the order they are received
+type Map struct {
+	entries []MapEntry
+	// index maps string form of the key to the first entry with such key
+	index map[string]int
+}
+
+type MapEntry struct {
+	Key   Attribute
+	Value Attribute
+}
+
+func NewMap(entries ...MapEntry) Map {
+	m := Map{}
+	for _, e := range entries {
+		m.add(e.Key, e.Value)
+	}
+	return m
+}
+
+func (m *Map) add(key, value Attribute) {
+	if m.index == nil {
+		m.index = make(map[string]int)
+	}
+	if _, ok := m.index[key.ToString()]; !ok {
+		m.index[key.ToString()] = len(m.entries)
+	}
+	m.entries = append(m.entries, MapEntry{Key: key, Value: value})
+}
+
+func (m Map) Len() int {
+	return len(m.entries)
+}
+
+// Entries returns map entries in the received order
+func (m Map) Entries() []MapEntry {
+	return m.entries
+}
+
+func (m Map) Keys() List {
+	keys := make(List, 0, len(m.entries))
+	for _, e := range m.entries {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
+
+// Lookup finds value by string form of the key, so T.id key is found by "id" and Int32 1 by "1"
+func (m Map) Lookup(key string) (Attribute, bool) {
+	i, ok := m.index[key]
+	if !ok {
+		return Attribute{}, false
+	}
+	return m.entries[i].Value, true
+}
+
+// Get returns value by string form of the key or empty Attribute if there is no such key
+func (m Map) Get(key string) Attribute {
+	v, _ := m.Lookup(key)
+	return v
+}
+
+// LookupKey finds value by typed key
+func (m Map) LookupKey(key Attribute) (Attribute, bool) {
+	for _, e := range m.entries {
+		if e.Key.Type == key.Type && reflect.DeepEqual(e.Key.Value, key.Value) {
+			return e.Value, true
+		}
+	}
+	return Attribute{}, false
+}
+
+// UnmarshalJSON reads GraphSON 3 g:Map value, which is a flat list of alternating keys and values.
+// A plain json object is accepted as well, its keys are strings.
+func (m *Map) UnmarshalJSON(b []byte) error {
+	*m = Map{}
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '{' {
+		return m.unmarshalObject(b)
+	}
+	var key Attribute
+	var n int
+	var skipped bool
+	err := unmarshalItems(b, func(a Attribute, skip bool) {
+		if n%2 == 0 {
+			key, skipped = a, skip
+		} else if !skipped && !skip {
+			// an entry is dropped when its key or value is skipped
+			m.add(key, a)
+		}
+		n++
+	})
+	if err != nil {
+		return err
+	}
+	if n%2 != 0 {
+		return fmt.Errorf("%s has a key without value", TypeMap)
+	}
+	return nil
+}
+
+// unmarshalObject reads json object keeping order of its keys
+func (m *Map) unmarshalObject(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("wrong object key: %v", tok)
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		var value Attribute
+		err = value.unmarshal(raw)
+		if err == errSkipped {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		m.add(Attribute{Type: TypeString, Value: key}, value)
+	}
+	_, err := dec.Token()
+	return err
+}
 
 type Property struct {
 	Key   string    `json:"key"`
@@ -213,23 +560,40 @@ type Attribute struct {
 	Value interface{}
 }
 
+// UnmarshalJSON reads GraphSON 3 and GraphSON 2 typed values,
+// untyped values of GraphSON 1 and GraphSON 2 are read as described in unmarshalUntyped.
+// Values of any version are read, see UnmarshalListVersion to read records of one version.
+// Types missing in dbTypes are read as described in RegisterType and UnknownTypePolicy.
 func (a *Attribute) UnmarshalJSON(b []byte) error {
-	var rec dbRecord
-	err := json.Unmarshal(b, &rec)
-	if err == nil {
-		return a.unmarshalByType(&rec)
-	}
-	// in case if value is string (not Attribute)
-	jsErr, ok := err.(*json.UnmarshalTypeError)
-	if !ok || jsErr.Value != "string" { //"number" "bool"
+	err := a.unmarshal(b)
+	if err == errSkipped {
+		*a = Attribute{}
+		return nil
+	}
+	return err
+}
+
+// unmarshal is UnmarshalJSON returning errSkipped for values dropped by UnknownTypeSkip
+func (a *Attribute) unmarshal(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if isNullValue(b) {
+		*a = Attribute{}
+		return nil
+	}
+	if b[0] != '{' {
+		return a.unmarshalUntyped(b)
+	}
+	// the object is read in one pass as a typed value or as an untyped map, see readObject
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if _, err := dec.Token(); err != nil {
 		return err
 	}
-	var s string
-	if err := json.Unmarshal(b, &s); err != nil {
+	v, err := readObject(dec)
+	if err != nil {
 		return err
 	}
-	a.Type = TypeString
-	a.Value = s
+	*a = v
 	return nil
 }
 
@@ -239,7 +603,7 @@ func (a *Attribute) unmarshalByType(rec *dbRecord) error {
 	}
 	t, ok := dbTypes[rec.Type]
 	if !ok {
-		return fmt.Errorf("unknown type: %s", a.Type)
+		return a.unmarshalCustomType(rec)
 	}
 	a.Type = t
 	unmarshalValue, ok := dbUnmarshals[a.Type]
@@ -284,6 +648,14 @@ func (a Attribute) Int32Value() int32 {
 	return v
 }
 
+func (a Attribute) Int64Value() int64 {
+	v, ok := a.Value.(int64)
+	if !ok {
+		return 0
+	}
+	return v
+}
+
 func (a Attribute) Float64Value() float64 {
 	v, ok := a.Value.(float64)
 	if !ok {
@@ -292,6 +664,46 @@ func (a Attribute) Float64Value() float64 {
 	return v
 }
 
+func (a Attribute) TimeValue() time.Time {
+	v, ok := a.Value.(time.Time)
+	if !ok {
+		return time.Time{}
+	}
+	return v
+}
+
+// UUIDValue returns lowercase uuid string
+func (a Attribute) UUIDValue() string {
+	if a.Type != TypeUUID {
+		return ""
+	}
+	return a.StringValue()
+}
+
+// ClassValue returns java class name
+func (a Attribute) ClassValue() string {
+	if a.Type != TypeClass {
+		return ""
+	}
+	return a.StringValue()
+}
+
+func (a Attribute) TValue() T {
+	v, ok := a.Value.(T)
+	if !ok {
+		return ""
+	}
+	return v
+}
+
+func (a Attribute) DirectionValue() Direction {
+	v, ok := a.Value.(Direction)
+	if !ok {
+		return ""
+	}
+	return v
+}
+
 func (a Attribute) PropertyValue() Property {
 	v, ok := a.Value.(Property)
 	if !ok {
@@ -319,7 +731,7 @@ func (a Attribute) ListValue() List {
 func (a Attribute) MapValue() Map {
 	v, ok := a.Value.(Map)
 	if !ok {
-		return make(Map)
+		return Map{}
 	}
 	return v
 }
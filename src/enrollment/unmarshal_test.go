@@ -2,7 +2,9 @@ package enrollment
 
 import (
 	"encoding/json"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -160,3 +162,173 @@ func TestAttribute_UnmarshalJSON_Float(t *testing.T) {
 	assert.Equal(t, TypeFloat, a.Type)
 	assert.Equal(t, float64(1.23), a.Float64Value())
 }
+
+func TestAttribute_UnmarshalJSON_Map(t *testing.T) {
+	js := []byte(`{"@type":"g:Map","@value":["sitter_id","s1","years_of_exp",{"@type":"g:Int32","@value":10}]}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeMap, a.Type)
+	v := a.MapValue()
+	assert.Equal(t, 2, v.Len())
+	assert.Equal(t, "s1", v.Get("sitter_id").StringValue())
+	assert.Equal(t, int32(10), v.Get("years_of_exp").Int32Value())
+}
+
+func TestAttribute_UnmarshalJSON_MapTypedKeys(t *testing.T) {
+	js := []byte(`{"@type":"g:Map","@value":[` +
+		`{"@type":"g:T","@value":"id"},{"@type":"g:Int64","@value":4136},` +
+		`{"@type":"g:T","@value":"label"},"provider",` +
+		`{"@type":"g:Int32","@value":7},"seven",` +
+		`"name",{"@type":"g:List","@value":["Ann"]}]}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeMap, a.Type)
+	v := a.MapValue()
+	require.Equal(t, 4, v.Len())
+
+	keys := v.Keys()
+	assert.Equal(t, Attribute{Type: TypeT, Value: TID}, keys[0])
+	assert.Equal(t, Attribute{Type: TypeT, Value: TLabel}, keys[1])
+	assert.Equal(t, Attribute{Type: TypeInteger, Value: int32(7)}, keys[2])
+	assert.Equal(t, Attribute{Type: TypeString, Value: "name"}, keys[3])
+
+	assert.Equal(t, int64(4136), v.Get("id").Int64Value())
+	assert.Equal(t, "provider", v.Get("label").StringValue())
+	assert.Equal(t, "seven", v.Get("7").StringValue())
+
+	seven, ok := v.LookupKey(Attribute{Type: TypeInteger, Value: int32(7)})
+	assert.True(t, ok)
+	assert.Equal(t, "seven", seven.StringValue())
+	_, ok = v.LookupKey(Attribute{Type: TypeString, Value: "7"})
+	assert.False(t, ok)
+	_, ok = v.Lookup("age")
+	assert.False(t, ok)
+}
+
+func TestAttribute_UnmarshalJSON_MapObject(t *testing.T) {
+	js := []byte(`{"@type":"g:Map","@value":{"b":"2","a":{"@type":"g:Int32","@value":1}}}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	v := a.MapValue()
+	require.Equal(t, 2, v.Len())
+	assert.Equal(t, "b", v.Entries()[0].Key.StringValue())
+	assert.Equal(t, int32(1), v.Get("a").Int32Value())
+}
+
+func TestAttribute_UnmarshalJSON_Int64(t *testing.T) {
+	js := []byte(`{"@type":"g:Int64","@value":9007199254740993}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeLong, a.Type)
+	assert.Equal(t, int64(9007199254740993), a.Int64Value())
+}
+
+func TestAttribute_UnmarshalJSON_Long(t *testing.T) {
+	js := []byte(`{"@type":"g:Long","@value":-42}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeLong, a.Type)
+	assert.Equal(t, int64(-42), a.Int64Value())
+}
+
+func TestAttribute_UnmarshalJSON_Double(t *testing.T) {
+	js := []byte(`{"@type":"g:Double","@value":4.25}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeDouble, a.Type)
+	assert.Equal(t, 4.25, a.Float64Value())
+
+	js = []byte(`{"@type":"g:Double","@value":"-Infinity"}`)
+	err = json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.True(t, math.IsInf(a.Float64Value(), -1))
+
+	js = []byte(`{"@type":"g:Double","@value":"NaN"}`)
+	err = json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(a.Float64Value()))
+}
+
+func TestAttribute_UnmarshalJSON_Date(t *testing.T) {
+	js := []byte(`{"@type":"g:Date","@value":1602266400123}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeDate, a.Type)
+	assert.Equal(t, time.Date(2020, time.October, 9, 18, 0, 0, 123000000, time.UTC), a.TimeValue())
+}
+
+func TestAttribute_UnmarshalJSON_Timestamp(t *testing.T) {
+	js := []byte(`{"@type":"g:Timestamp","@value":1602266400000}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeTimestamp, a.Type)
+	assert.Equal(t, time.Date(2020, time.October, 9, 18, 0, 0, 0, time.UTC), a.TimeValue())
+}
+
+func TestAttribute_UnmarshalJSON_DateOutOfNanoRange(t *testing.T) {
+	tests := []struct {
+		js   string
+		want time.Time
+	}{
+		{`{"@type":"g:Date","@value":253402300799000}`, time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)},
+		{`{"@type":"g:Date","@value":-62135596799999}`, time.Date(1, time.January, 1, 0, 0, 0, 1000000, time.UTC)},
+		{`{"@type":"g:Timestamp","@value":253402300799999}`, time.Date(9999, time.December, 31, 23, 59, 59, 999000000, time.UTC)},
+		{`{"@type":"g:Timestamp","@value":-62135596800000}`, time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		var a Attribute
+		require.NoError(t, json.Unmarshal([]byte(tt.js), &a), tt.js)
+		assert.Equal(t, tt.want, a.TimeValue(), tt.js)
+	}
+}
+
+func TestAttribute_UnmarshalJSON_UUID(t *testing.T) {
+	js := []byte(`{"@type":"g:UUID","@value":"41D2E28A-20A4-4AB0-B379-D810DEDE3786"}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeUUID, a.Type)
+	assert.Equal(t, "41d2e28a-20a4-4ab0-b379-d810dede3786", a.UUIDValue())
+
+	js = []byte(`{"@type":"g:UUID","@value":"41d2e28a"}`)
+	assert.Error(t, json.Unmarshal(js, &a))
+}
+
+func TestAttribute_UnmarshalJSON_Class(t *testing.T) {
+	js := []byte(`{"@type":"g:Class","@value":"java.io.File"}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeClass, a.Type)
+	assert.Equal(t, "java.io.File", a.ClassValue())
+}
+
+func TestAttribute_UnmarshalJSON_T(t *testing.T) {
+	js := []byte(`{"@type":"g:T","@value":"label"}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeT, a.Type)
+	assert.Equal(t, TLabel, a.TValue())
+	assert.Equal(t, "label", a.ToString())
+
+	js = []byte(`{"@type":"g:T","@value":"name"}`)
+	assert.Error(t, json.Unmarshal(js, &a))
+}
+
+func TestAttribute_UnmarshalJSON_Direction(t *testing.T) {
+	js := []byte(`{"@type":"g:Direction","@value":"OUT"}`)
+	var a Attribute
+	err := json.Unmarshal(js, &a)
+	require.NoError(t, err)
+	assert.Equal(t, TypeDirection, a.Type)
+	assert.Equal(t, DirectionOut, a.DirectionValue())
+}
@@ -0,0 +1,134 @@
+package enrollment
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const DefaultMaxPageSize int32 = 100
+
+// CareTypes are services known to the graph
+var CareTypes = map[string]bool{
+	"childCare":  true,
+	"petCare":    true,
+	"seniorCare": true,
+	"houseCare":  true,
+}
+
+var postalCodePattern = regexp.MustCompile(`^[0-9]{5}(-[0-9]{4})?$`)
+
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Error())
+	}
+	return "invalid request: " + strings.Join(msgs, "; ")
+}
+
+// errEmptyRequest is returned for a nil request, it is a wrong request as any other
+var errEmptyRequest = ValidationErrors{{Field: "Request", Message: "must not be empty"}}
+
+// Validate checks request fields and clamps PageSize to maxPageSize, a non-positive maxPageSize does not clamp.
+// Field errors are returned as ValidationErrors,
+// ErrNoPageTokenSecret is returned as is, it is a server misconfiguration and not a wrong request.
+func Validate(req *GRPCModel, maxPageSize int32) error {
+	if req == nil {
+		return errEmptyRequest
+	}
+	var errs ValidationErrors
+	add := func(field, format string, args ...interface{}) {
+		errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	if len(req.PostalCode) > 0 && !postalCodePattern.MatchString(req.PostalCode) {
+		add("PostalCode", "%q is not a valid postal code", req.PostalCode)
+	}
+	if len(req.CareType) > 0 && !CareTypes[req.CareType] {
+		add("CareType", "unknown care type %q", req.CareType)
+	}
+	for i, ct := range req.CareTypes {
+		if !CareTypes[ct] {
+			add(fmt.Sprintf("CareTypes[%d]", i), "unknown care type %q", ct)
+		}
+	}
+	switch req.CareTypeMatch {
+	case "", MatchAnyCareType, MatchAllCareTypes:
+	default:
+		add("CareTypeMatch", "unknown care type match %q", req.CareTypeMatch)
+	}
+	if req.MaxDistance < 0 {
+		add("MaxDistance", "must not be negative")
+	}
+	if req.MaxDistance > 0 && len(req.PostalCode) == 0 {
+		add("MaxDistance", "requires PostalCode")
+	}
+	if req.MinYearsOfExp < 0 {
+		add("MinYearsOfExp", "must not be negative")
+	}
+	if req.MinAvgRank < 0 {
+		add("MinAvgRank", "must not be negative")
+	}
+	if req.ActiveWithinDays < 0 {
+		add("ActiveWithinDays", "must not be negative")
+	}
+	if rate := req.HourlyRate; rate != nil {
+		if rate.Min < 0 {
+			add("HourlyRate.Min", "must not be negative")
+		}
+		if rate.Max < 0 {
+			add("HourlyRate.Max", "must not be negative")
+		}
+		if rate.Max > 0 && rate.Min > rate.Max {
+			add("HourlyRate", "min %v is greater than max %v", rate.Min, rate.Max)
+		}
+	}
+	if window := req.Availability; window != nil {
+		if !inAvailabilityYears(window.From) || !inAvailabilityYears(window.To) {
+			add("Availability", "window must be within years %d-%d", minAvailabilityYear, maxAvailabilityYear)
+		} else if !window.To.After(window.From) {
+			add("Availability", "To must be after From")
+		} else if window.To.Sub(window.From) > MaxAvailabilityWindow {
+			add("Availability", "window must not be longer than %s", MaxAvailabilityWindow)
+		}
+	}
+	if req.Sort != nil {
+		if _, _, err := getSort(req); err != nil {
+			add("Sort", err.Error())
+		}
+	}
+	switch req.Projection {
+	case ProjectSitterIDs, ProjectProfiles:
+	default:
+		add("Projection", "unknown projection %q", req.Projection)
+	}
+	if req.PageSize < 0 {
+		add("PageSize", "must not be negative")
+	}
+	if maxPageSize > 0 && req.PageSize > maxPageSize {
+		req.PageSize = maxPageSize
+	}
+	if len(req.PageToken) > 0 {
+		_, err := decodePageToken(req.PageToken)
+		if err == ErrNoPageTokenSecret {
+			return err
+		}
+		if err != nil {
+			add("PageToken", err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package enrollment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	req := &GRPCModel{
+		PostalCode: "78704-1234",
+		CareType:   "childCare",
+		HourlyRate: &HourlyRateGRPCModel{Min: 10},
+		PageSize:   1000,
+	}
+	require.NoError(t, Validate(req, 50))
+	assert.Equal(t, int32(50), req.PageSize)
+
+	// non-positive max does not clamp
+	req.PageSize = 1000
+	require.NoError(t, Validate(req, 0))
+	assert.Equal(t, int32(1000), req.PageSize)
+}
+
+func TestValidate_FieldErrors(t *testing.T) {
+	req := &GRPCModel{
+		PostalCode:  "7870",
+		CareType:    "dogWalking",
+		MaxDistance: -1,
+		HourlyRate:  &HourlyRateGRPCModel{Min: 50, Max: 20},
+		Sort:        &SortGRPCModel{Field: "distance"},
+		Projection:  "everything",
+		PageSize:    -5,
+		PageToken:   "2",
+	}
+	err := Validate(req, 50)
+	require.Error(t, err)
+	errs, ok := err.(ValidationErrors)
+	require.True(t, ok)
+
+	var fields []string
+	for _, fe := range errs {
+		fields = append(fields, fe.Field)
+	}
+	assert.Equal(t, []string{"PostalCode", "CareType", "MaxDistance", "HourlyRate", "Sort", "Projection", "PageSize", "PageToken"}, fields)
+}
+
+func TestValidate_RadiusWithoutPostalCode(t *testing.T) {
+	err := Validate(&GRPCModel{MaxDistance: 5}, DefaultMaxPageSize)
+	assert.Equal(t, ValidationErrors{{Field: "MaxDistance", Message: "requires PostalCode"}}, err)
+}
+
+func TestValidate_CareTypes(t *testing.T) {
+	err := Validate(&GRPCModel{CareTypes: []string{"petCare", "dogWalking"}, CareTypeMatch: "some"}, DefaultMaxPageSize)
+	assert.Equal(t, ValidationErrors{
+		{Field: "CareTypes[1]", Message: `unknown care type "dogWalking"`},
+		{Field: "CareTypeMatch", Message: `unknown care type match "some"`},
+	}, err)
+}
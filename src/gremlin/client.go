@@ -0,0 +1,172 @@
+package gremlin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/akhripko/gremlin-grammes/src/enrollment"
+	"github.com/akhripko/gremlin-grammes/src/options"
+	"github.com/northwesternmutual/grammes"
+	"github.com/northwesternmutual/grammes/gremconnect"
+)
+
+// Dial connects to the gremlin server at addr with the serializer, see options.Config
+func Dial(addr, serializer string, cfgs ...grammes.ClientConfiguration) (*grammes.Client, error) {
+	dialer, err := NewDialer(grammes.NewWebSocketDialer(addr), serializer)
+	if err != nil {
+		return nil, err
+	}
+	return grammes.Dial(dialer, cfgs...)
+}
+
+// NewDialer wraps the dialer, so the traffic goes in the serializer format
+func NewDialer(dialer gremconnect.Dialer, serializer string) (gremconnect.Dialer, error) {
+	switch serializer {
+	case "", options.SerializerGraphSON:
+		return dialer, nil
+	case options.SerializerGraphBinary:
+		return &graphBinaryDialer{Dialer: dialer}, nil
+	}
+	return nil, fmt.Errorf("unknown gremlin serializer: %s", serializer)
+}
+
+// graphBinaryDialer sends and receives GraphBinary messages.
+// grammes client writes GraphSON requests and reads GraphSON responses,
+// so requests are converted to GraphBinary and responses are converted back to GraphSON 3.
+type graphBinaryDialer struct {
+	gremconnect.Dialer
+}
+
+type graphSONResponse struct {
+	RequestID string                 `json:"requestId"`
+	Status    graphSONResponseStatus `json:"status"`
+	Result    graphSONResponseResult `json:"result"`
+}
+
+type graphSONResponseStatus struct {
+	Code       int32                `json:"code"`
+	Message    string               `json:"message"`
+	Attributes enrollment.Attribute `json:"attributes"`
+}
+
+type graphSONResponseResult struct {
+	Data enrollment.Attribute `json:"data"`
+	Meta enrollment.Attribute `json:"meta"`
+}
+
+func (d *graphBinaryDialer) Write(msg []byte) error {
+	req, err := toGraphBinaryRequest(msg)
+	if err != nil {
+		return err
+	}
+	b, err := enrollment.MarshalGraphBinaryRequest(req)
+	if err != nil {
+		return err
+	}
+	return d.Dialer.Write(b)
+}
+
+func (d *graphBinaryDialer) Read() ([]byte, error) {
+	msg, err := d.Dialer.Read()
+	if err != nil || msg == nil {
+		return msg, err
+	}
+	resp, err := enrollment.UnmarshalGraphBinaryResponse(msg)
+	if err != nil {
+		return nil, err
+	}
+	return toGraphSONResponse(resp)
+}
+
+// toGraphBinaryRequest reads GraphSON request, which starts with the mime type header.
+// grammes writes args as plain json, so they are read with encoding/json and not as GraphSON values.
+func toGraphBinaryRequest(msg []byte) (*enrollment.GraphBinaryRequest, error) {
+	if len(msg) == 0 || len(msg) < int(msg[0])+1 {
+		return nil, errors.New("mime type header is missing")
+	}
+	var req gremconnect.Request
+	dec := json.NewDecoder(bytes.NewReader(msg[msg[0]+1:]))
+	dec.UseNumber()
+	if err := dec.Decode(&req); err != nil {
+		return nil, err
+	}
+	args, err := argValue(req.Args)
+	if err != nil {
+		return nil, err
+	}
+	return &enrollment.GraphBinaryRequest{
+		RequestID: req.RequestID,
+		Op:        req.Op,
+		Processor: req.Processor,
+		Args:      args.MapValue(),
+	}, nil
+}
+
+// argValue converts a request arg read by encoding/json, objects are maps with keys in sorted order
+func argValue(v interface{}) (enrollment.Attribute, error) {
+	switch v := v.(type) {
+	case nil:
+		return enrollment.Attribute{}, nil
+	case string:
+		return enrollment.Attribute{Type: enrollment.TypeString, Value: v}, nil
+	case bool:
+		return enrollment.Attribute{Type: enrollment.TypeBoolean, Value: v}, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			if i >= math.MinInt32 && i <= math.MaxInt32 {
+				return enrollment.Attribute{Type: enrollment.TypeInteger, Value: int32(i)}, nil
+			}
+			return enrollment.Attribute{Type: enrollment.TypeLong, Value: i}, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return enrollment.Attribute{}, fmt.Errorf("wrong request arg: %s", v)
+		}
+		return enrollment.Attribute{Type: enrollment.TypeDouble, Value: f}, nil
+	case []interface{}:
+		items := make(enrollment.List, 0, len(v))
+		for _, item := range v {
+			a, err := argValue(item)
+			if err != nil {
+				return enrollment.Attribute{}, err
+			}
+			items = append(items, a)
+		}
+		return enrollment.Attribute{Type: enrollment.TypeList, Value: items}, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		entries := make([]enrollment.MapEntry, 0, len(keys))
+		for _, k := range keys {
+			a, err := argValue(v[k])
+			if err != nil {
+				return enrollment.Attribute{}, err
+			}
+			entries = append(entries, enrollment.MapEntry{Key: enrollment.Attribute{Type: enrollment.TypeString, Value: k}, Value: a})
+		}
+		return enrollment.Attribute{Type: enrollment.TypeMap, Value: enrollment.NewMap(entries...)}, nil
+	}
+	return enrollment.Attribute{}, fmt.Errorf("unsupported request arg: %T", v)
+}
+
+func toGraphSONResponse(resp *enrollment.GraphBinaryResponse) ([]byte, error) {
+	return json.Marshal(graphSONResponse{
+		RequestID: resp.RequestID,
+		Status: graphSONResponseStatus{
+			Code:       resp.StatusCode,
+			Message:    resp.StatusMessage,
+			Attributes: enrollment.Attribute{Type: enrollment.TypeMap, Value: resp.StatusAttributes},
+		},
+		Result: graphSONResponseResult{
+			Data: resp.Data,
+			Meta: enrollment.Attribute{Type: enrollment.TypeMap, Value: resp.ResultMeta},
+		},
+	})
+}
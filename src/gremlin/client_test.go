@@ -0,0 +1,207 @@
+package gremlin
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/akhripko/gremlin-grammes/src/enrollment"
+	"github.com/akhripko/gremlin-grammes/src/options"
+	"github.com/northwesternmutual/grammes"
+	"github.com/northwesternmutual/grammes/gremconnect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	if err := enrollment.SetPageTokenSecret([]byte("test secret")); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeServer is a dialer answering every request with the same data in GraphSON 3 or GraphBinary
+type fakeServer struct {
+	binary    bool
+	data      enrollment.Attribute
+	queries   chan string
+	responses chan []byte
+	quit      chan struct{}
+}
+
+func newFakeServer(binary bool, data enrollment.Attribute) *fakeServer {
+	return &fakeServer{
+		binary:    binary,
+		data:      data,
+		queries:   make(chan string, 10),
+		responses: make(chan []byte, 10),
+		quit:      make(chan struct{}),
+	}
+}
+
+func (s *fakeServer) Write(msg []byte) error {
+	if s.binary {
+		req, err := enrollment.UnmarshalGraphBinaryRequest(msg)
+		if err != nil {
+			return err
+		}
+		s.queries <- req.Args.Get("gremlin").StringValue()
+		resp, err := enrollment.MarshalGraphBinaryResponse(&enrollment.GraphBinaryResponse{
+			RequestID:  req.RequestID,
+			StatusCode: 200,
+			Data:       s.data,
+		})
+		if err != nil {
+			return err
+		}
+		s.responses <- resp
+		return nil
+	}
+	req, err := toGraphBinaryRequest(msg)
+	if err != nil {
+		return err
+	}
+	s.queries <- req.Args.Get("gremlin").StringValue()
+	resp, err := toGraphSONResponse(&enrollment.GraphBinaryResponse{
+		RequestID:  req.RequestID,
+		StatusCode: 200,
+		Data:       s.data,
+	})
+	if err != nil {
+		return err
+	}
+	s.responses <- resp
+	return nil
+}
+
+func (s *fakeServer) Read() ([]byte, error) {
+	select {
+	case msg := <-s.responses:
+		return msg, nil
+	case <-s.quit:
+		return nil, nil
+	}
+}
+
+func (s *fakeServer) Connect() error                   { return nil }
+func (s *fakeServer) Close() error                     { close(s.quit); return nil }
+func (s *fakeServer) Ping(chan error)                  {}
+func (s *fakeServer) IsConnected() bool                { return true }
+func (s *fakeServer) IsDisposed() bool                 { return false }
+func (s *fakeServer) Auth() (*gremconnect.Auth, error) { return nil, nil }
+func (s *fakeServer) Address() string                  { return "fake" }
+func (s *fakeServer) GetQuit() chan struct{}           { return s.quit }
+func (s *fakeServer) SetAuth(string, string)           {}
+func (s *fakeServer) SetTimeout(time.Duration)         {}
+func (s *fakeServer) SetPingInterval(time.Duration)    {}
+func (s *fakeServer) SetWritingWait(time.Duration)     {}
+func (s *fakeServer) SetReadingWait(time.Duration)     {}
+func (s *fakeServer) SetTLSConfig(*tls.Config)         {}
+
+const providersResult = `{"@type":"g:List","@value":[` +
+	`{"@type":"g:Map","@value":["sitter_id","s1","sort_key",{"@type":"g:Float","@value":4.5},` +
+	`"gender",{"@type":"g:List","@value":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":10},"value":"female","label":"gender"}}]},` +
+	`"avg_rank",{"@type":"g:List","@value":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":11},"value":{"@type":"g:Float","@value":4.5},"label":"avg_rank"}}]},` +
+	`"years_of_exp",{"@type":"g:List","@value":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":12},"value":{"@type":"g:Int32","@value":3},"label":"years_of_exp"}}]},` +
+	`"service",{"@type":"g:List","@value":[{"@type":"g:Property","@value":{"key":"service","value":"childCare"}}]},` +
+	`"min_rate",{"@type":"g:List","@value":[{"@type":"g:Property","@value":{"key":"min_rate","value":{"@type":"g:Double","@value":12.5}}}]},` +
+	`"max_rate",{"@type":"g:List","@value":[{"@type":"g:Property","@value":{"key":"max_rate","value":{"@type":"g:Double","@value":30}}}]},` +
+	`"zip",{"@type":"g:List","@value":[{"@type":"g:VertexProperty","@value":{"id":{"@type":"g:Int64","@value":13},"value":"78704","label":"name"}}]}]},` +
+	`{"@type":"g:Map","@value":["sitter_id","s2","sort_key",{"@type":"g:Float","@value":4},` +
+	`"gender",{"@type":"g:List","@value":[]},"avg_rank",{"@type":"g:List","@value":[]},"years_of_exp",{"@type":"g:List","@value":[]},` +
+	`"service",{"@type":"g:List","@value":[]},"min_rate",{"@type":"g:List","@value":[]},"max_rate",{"@type":"g:List","@value":[]},` +
+	`"zip",{"@type":"g:List","@value":[]}]}]}`
+
+func search(t *testing.T, serializer string, data enrollment.Attribute) ([][]byte, *enrollment.GRPCResponseModel) {
+	server := newFakeServer(serializer == options.SerializerGraphBinary, data)
+	dialer, err := NewDialer(server, serializer)
+	require.NoError(t, err)
+	client, err := grammes.Dial(dialer)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := &enrollment.GRPCModel{
+		CareType:   "childCare",
+		Sort:       &enrollment.SortGRPCModel{Field: enrollment.SortByAvgRank, Direction: enrollment.SortDesc},
+		Projection: enrollment.ProjectProfiles,
+		PageSize:   1,
+	}
+	query, bindings, err := enrollment.BuildQuery(req)
+	require.NoError(t, err)
+	recs, err := client.ExecuteBoundQuery(query, bindings, nil)
+	require.NoError(t, err)
+	assert.Equal(t, query.String(), <-server.queries)
+
+	res, err := enrollment.BuildResponse(req, recs)
+	require.NoError(t, err)
+	return recs, res
+}
+
+func TestDial_GraphSONAndGraphBinaryDecodeSameResult(t *testing.T) {
+	var data enrollment.Attribute
+	require.NoError(t, json.Unmarshal([]byte(providersResult), &data))
+
+	graphSONRecs, graphSONRes := search(t, options.SerializerGraphSON, data)
+	graphBinaryRecs, graphBinaryRes := search(t, options.SerializerGraphBinary, data)
+
+	graphSONList, err := enrollment.UnmarshalList(graphSONRecs)
+	require.NoError(t, err)
+	graphBinaryList, err := enrollment.UnmarshalList(graphBinaryRecs)
+	require.NoError(t, err)
+	assert.Equal(t, data.ListValue(), graphSONList)
+	assert.Equal(t, graphSONList, graphBinaryList)
+
+	assert.Equal(t, graphSONRes, graphBinaryRes)
+	assert.Equal(t, []string{"s1"}, graphBinaryRes.SitterIDs)
+	require.Len(t, graphBinaryRes.Providers, 1)
+	assert.Equal(t, enrollment.ProviderResult{
+		SitterID:   "s1",
+		Gender:     "female",
+		AvgRank:    4.5,
+		YearsOfExp: 3,
+		Service:    "childCare",
+		MinRate:    12.5,
+		MaxRate:    30,
+		ZIP:        "78704",
+	}, graphBinaryRes.Providers[0])
+	assert.NotEmpty(t, graphBinaryRes.NextPageToken)
+}
+
+func TestToGraphBinaryRequest(t *testing.T) {
+	req, id, err := gremconnect.PrepareRequest("g.V().has('sitter_id', sid)", map[string]string{"sid": "s1"}, nil)
+	require.NoError(t, err)
+	msg, err := gremconnect.PackageRequest(req, "3")
+	require.NoError(t, err)
+
+	binReq, err := toGraphBinaryRequest(msg)
+	require.NoError(t, err)
+	assert.Equal(t, id, binReq.RequestID)
+	assert.Equal(t, "eval", binReq.Op)
+	assert.Equal(t, "g.V().has('sitter_id', sid)", binReq.Args.Get("gremlin").StringValue())
+	assert.Equal(t, "gremlin-groovy", binReq.Args.Get("language").StringValue())
+	assert.Equal(t, "s1", binReq.Args.Get("bindings").MapValue().Get("sid").StringValue())
+	assert.Equal(t, enrollment.Attribute{}, binReq.Args.Get("rebindings"))
+
+	b, err := enrollment.MarshalGraphBinaryRequest(binReq)
+	require.NoError(t, err)
+	decoded, err := enrollment.UnmarshalGraphBinaryRequest(b)
+	require.NoError(t, err)
+	assert.Equal(t, binReq, decoded)
+}
+
+func TestDial_GraphBinaryReadsGraphSONV3(t *testing.T) {
+	var data enrollment.Attribute
+	require.NoError(t, json.Unmarshal([]byte(providersResult), &data))
+
+	recs, _ := search(t, options.SerializerGraphBinary, data)
+	items, err := enrollment.UnmarshalListVersion(recs, enrollment.GraphSONV3)
+	require.NoError(t, err)
+	assert.Equal(t, data.ListValue(), items)
+}
+
+func TestNewDialer_UnknownSerializer(t *testing.T) {
+	_, err := NewDialer(newFakeServer(false, enrollment.Attribute{}), "gryo")
+	assert.Error(t, err)
+}
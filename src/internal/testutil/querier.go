@@ -0,0 +1,65 @@
+// Package testutil holds fakes shared by tests of the search packages
+package testutil
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/northwesternmutual/grammes/query"
+)
+
+// Querier is a fake manager.ExecuteQuerier answering count queries with Count and other queries with Page
+type Querier struct {
+	Page  string
+	Count string
+	Err   error
+	// Block holds queries until it is closed
+	Block chan struct{}
+
+	mu       sync.Mutex
+	queries  []string
+	bindings []map[string]string
+}
+
+func (f *Querier) ExecuteQuery(q query.Query) ([][]byte, error) {
+	return f.ExecuteBoundStringQuery(q.String(), nil, nil)
+}
+
+func (f *Querier) ExecuteStringQuery(q string) ([][]byte, error) {
+	return f.ExecuteBoundStringQuery(q, nil, nil)
+}
+
+func (f *Querier) ExecuteBoundQuery(q query.Query, bindings, rebindings map[string]string) ([][]byte, error) {
+	return f.ExecuteBoundStringQuery(q.String(), bindings, rebindings)
+}
+
+func (f *Querier) ExecuteBoundStringQuery(q string, bindings, _ map[string]string) ([][]byte, error) {
+	if f.Block != nil {
+		<-f.Block
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries = append(f.queries, q)
+	f.bindings = append(f.bindings, bindings)
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if strings.HasSuffix(q, ".count()") {
+		return [][]byte{[]byte(f.Count)}, nil
+	}
+	return [][]byte{[]byte(f.Page)}, nil
+}
+
+// Queries returns the received queries in order
+func (f *Querier) Queries() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.queries...)
+}
+
+// Bindings returns bindings of the received queries in order
+func (f *Querier) Bindings() []map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]map[string]string(nil), f.bindings...)
+}
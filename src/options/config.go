@@ -1,5 +1,22 @@
 package options
 
+// Serializers of gremlin requests and responses
+const (
+	SerializerGraphSON    = "graphson"
+	SerializerGraphBinary = "graphbinary"
+)
+
 type Config struct {
+	// GRPCAddr is the address the search server listens on
+	GRPCAddr    string
 	GremlinAddr string
+	// GremlinSerializer is SerializerGraphSON or SerializerGraphBinary
+	GremlinSerializer string
+	// GremlinUnknownTypes is a policy for unknown GraphSON types: fail, raw or skip
+	GremlinUnknownTypes string
+	// GremlinGraphSONVersion is the GraphSON version of responses: auto, v1, v2 or v3
+	GremlinGraphSONVersion string
+	// PageTokenSecret is the key page tokens are signed with, it is required
+	PageTokenSecret string
+	MaxPageSize     int32
 }
@@ -9,9 +9,20 @@ func ReadEnv() *Config {
 
 	viper.SetEnvPrefix("APP")
 
+	viper.SetDefault("GRPC_ADDR", ":9090")
 	viper.SetDefault("GREMLIN_ADDR", "ws://127.0.0.1:8182")
+	viper.SetDefault("GREMLIN_SERIALIZER", SerializerGraphSON)
+	viper.SetDefault("GREMLIN_UNKNOWN_TYPES", "fail")
+	viper.SetDefault("GREMLIN_GRAPHSON_VERSION", "auto")
+	viper.SetDefault("MAX_PAGE_SIZE", 100)
 
 	return &Config{
-		GremlinAddr: viper.GetString("GREMLIN_ADDR"),
+		GRPCAddr:               viper.GetString("GRPC_ADDR"),
+		GremlinAddr:            viper.GetString("GREMLIN_ADDR"),
+		GremlinSerializer:      viper.GetString("GREMLIN_SERIALIZER"),
+		GremlinUnknownTypes:    viper.GetString("GREMLIN_UNKNOWN_TYPES"),
+		GremlinGraphSONVersion: viper.GetString("GREMLIN_GRAPHSON_VERSION"),
+		PageTokenSecret:        viper.GetString("PAGE_TOKEN_SECRET"),
+		MaxPageSize:            viper.GetInt32("MAX_PAGE_SIZE"),
 	}
 }
@@ -0,0 +1,4 @@
+// Package searchpb is the gRPC contract of the provider search, generated from search.proto
+package searchpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative search.proto
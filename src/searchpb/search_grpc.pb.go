@@ -0,0 +1,105 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: search.proto
+
+package searchpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ProviderSearchClient is the client API for ProviderSearch service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ProviderSearchClient interface {
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+}
+
+type providerSearchClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProviderSearchClient(cc grpc.ClientConnInterface) ProviderSearchClient {
+	return &providerSearchClient{cc}
+}
+
+func (c *providerSearchClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/search.v1.ProviderSearch/Search", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProviderSearchServer is the server API for ProviderSearch service.
+// All implementations must embed UnimplementedProviderSearchServer
+// for forward compatibility
+type ProviderSearchServer interface {
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	mustEmbedUnimplementedProviderSearchServer()
+}
+
+// UnimplementedProviderSearchServer must be embedded to have forward compatible implementations.
+type UnimplementedProviderSearchServer struct {
+}
+
+func (UnimplementedProviderSearchServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedProviderSearchServer) mustEmbedUnimplementedProviderSearchServer() {}
+
+// UnsafeProviderSearchServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProviderSearchServer will
+// result in compilation errors.
+type UnsafeProviderSearchServer interface {
+	mustEmbedUnimplementedProviderSearchServer()
+}
+
+func RegisterProviderSearchServer(s grpc.ServiceRegistrar, srv ProviderSearchServer) {
+	s.RegisterService(&ProviderSearch_ServiceDesc, srv)
+}
+
+func _ProviderSearch_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProviderSearchServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/search.v1.ProviderSearch/Search",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProviderSearchServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ProviderSearch_ServiceDesc is the grpc.ServiceDesc for ProviderSearch service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProviderSearch_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "search.v1.ProviderSearch",
+	HandlerType: (*ProviderSearchServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Search",
+			Handler:    _ProviderSearch_Search_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "search.proto",
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/akhripko/gremlin-grammes/src/enrollment"
+	"github.com/akhripko/gremlin-grammes/src/searchpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	sortFields = map[searchpb.SortField]enrollment.SortField{
+		searchpb.SortField_SORT_FIELD_AVG_RANK:     enrollment.SortByAvgRank,
+		searchpb.SortField_SORT_FIELD_YEARS_OF_EXP: enrollment.SortByYearsOfExp,
+		searchpb.SortField_SORT_FIELD_MIN_RATE:     enrollment.SortByMinRate,
+		searchpb.SortField_SORT_FIELD_MAX_RATE:     enrollment.SortByMaxRate,
+	}
+	sortDirections = map[searchpb.SortDirection]enrollment.SortDirection{
+		searchpb.SortDirection_SORT_DIRECTION_ASC:  enrollment.SortAsc,
+		searchpb.SortDirection_SORT_DIRECTION_DESC: enrollment.SortDesc,
+	}
+	careTypeMatches = map[searchpb.CareTypeMatch]enrollment.CareTypeMatch{
+		searchpb.CareTypeMatch_CARE_TYPE_MATCH_UNSPECIFIED: enrollment.MatchAnyCareType,
+		searchpb.CareTypeMatch_CARE_TYPE_MATCH_ANY:         enrollment.MatchAnyCareType,
+		searchpb.CareTypeMatch_CARE_TYPE_MATCH_ALL:         enrollment.MatchAllCareTypes,
+	}
+	projections = map[searchpb.Projection]enrollment.Projection{
+		searchpb.Projection_PROJECTION_SITTER_IDS: enrollment.ProjectSitterIDs,
+		searchpb.Projection_PROJECTION_PROFILES:   enrollment.ProjectProfiles,
+	}
+)
+
+// Server serves ProviderSearch with the searcher
+type Server struct {
+	searchpb.UnimplementedProviderSearchServer
+	searcher enrollment.Searcher
+}
+
+func New(searcher enrollment.Searcher) *Server {
+	return &Server{searcher: searcher}
+}
+
+// Register adds the server to grpc server s
+func (s *Server) Register(srv *grpc.Server) {
+	searchpb.RegisterProviderSearchServer(srv, s)
+}
+
+func (s *Server) Search(ctx context.Context, req *searchpb.SearchRequest) (*searchpb.SearchResponse, error) {
+	model, err := toModel(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	res, err := s.searcher.Search(ctx, model)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toResponse(res), nil
+}
+
+// toModel maps the proto request onto the request of enrollment.BuildQuery
+func toModel(req *searchpb.SearchRequest) (*enrollment.GRPCModel, error) {
+	model := &enrollment.GRPCModel{
+		PostalCode:       req.GetPostalCode(),
+		CareType:         req.GetCareType(),
+		CareTypes:        req.GetCareTypes(),
+		Gender:           req.GetGender(),
+		MinYearsOfExp:    req.GetMinYearsOfExp(),
+		MinAvgRank:       req.GetMinAvgRank(),
+		ActiveWithinDays: req.GetActiveWithinDays(),
+		MaxDistance:      req.GetMaxDistance(),
+		PageSize:         req.GetPageSize(),
+		PageToken:        req.GetPageToken(),
+	}
+	var ok bool
+	if model.CareTypeMatch, ok = careTypeMatches[req.GetCareTypeMatch()]; !ok {
+		return nil, errors.New("unknown care type match")
+	}
+	if model.Projection, ok = projections[req.GetProjection()]; !ok {
+		return nil, errors.New("unknown projection")
+	}
+	if rate := req.GetHourlyRate(); rate != nil {
+		model.HourlyRate = &enrollment.HourlyRateGRPCModel{Min: rate.GetMin(), Max: rate.GetMax()}
+	}
+	if window := req.GetAvailability(); window != nil {
+		if err := window.GetFrom().CheckValid(); err != nil {
+			return nil, errors.New("wrong availability from: " + err.Error())
+		}
+		if err := window.GetTo().CheckValid(); err != nil {
+			return nil, errors.New("wrong availability to: " + err.Error())
+		}
+		if len(window.GetTimeZone()) == 0 {
+			return nil, errors.New("availability time zone is required")
+		}
+		// slots are matched in the location of From, so the window is moved to the providers' local time
+		loc, err := time.LoadLocation(window.GetTimeZone())
+		if err != nil {
+			return nil, errors.New("wrong availability time zone: " + window.GetTimeZone())
+		}
+		model.Availability = &enrollment.TimeWindowGRPCModel{
+			From: window.GetFrom().AsTime().In(loc),
+			To:   window.GetTo().AsTime().In(loc),
+		}
+	}
+	if sort := req.GetSort(); sort != nil && sort.GetField() != searchpb.SortField_SORT_FIELD_UNSPECIFIED {
+		field, ok := sortFields[sort.GetField()]
+		if !ok {
+			return nil, errors.New("unknown sort field")
+		}
+		model.Sort = &enrollment.SortGRPCModel{Field: field}
+		if sort.GetDirection() != searchpb.SortDirection_SORT_DIRECTION_UNSPECIFIED {
+			if model.Sort.Direction, ok = sortDirections[sort.GetDirection()]; !ok {
+				return nil, errors.New("unknown sort direction")
+			}
+		}
+	}
+	return model, nil
+}
+
+func toResponse(res *enrollment.GRPCResponseModel) *searchpb.SearchResponse {
+	resp := &searchpb.SearchResponse{
+		SitterIds:     res.SitterIDs,
+		Distances:     res.Distances,
+		NextPageToken: res.NextPageToken,
+		Total:         res.Total,
+	}
+	for _, p := range res.Providers {
+		resp.Providers = append(resp.Providers, &searchpb.Provider{
+			SitterId:   p.SitterID,
+			Gender:     p.Gender,
+			AvgRank:    p.AvgRank,
+			YearsOfExp: p.YearsOfExp,
+			Service:    p.Service,
+			MinRate:    p.MinRate,
+			MaxRate:    p.MaxRate,
+			Zip:        p.ZIP,
+			Distance:   p.Distance,
+		})
+	}
+	return resp
+}
+
+// toStatus maps errors of enrollment.Searcher onto grpc codes
+func toStatus(err error) error {
+	var validation enrollment.ValidationErrors
+	var buildErr *enrollment.BuildError
+	var queryErr *enrollment.QueryError
+	switch {
+	case errors.Is(err, enrollment.ErrNoPageTokenSecret):
+		return status.Error(codes.Internal, err.Error())
+	case errors.As(err, &validation), errors.As(err, &buildErr),
+		errors.Is(err, enrollment.ErrPageTokenMismatch), errors.Is(err, enrollment.ErrInvalidPageToken):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.As(err, &queryErr):
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/akhripko/gremlin-grammes/src/enrollment"
+	"github.com/akhripko/gremlin-grammes/src/internal/testutil"
+	"github.com/akhripko/gremlin-grammes/src/searchpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestMain(m *testing.M) {
+	if err := enrollment.SetPageTokenSecret([]byte("test secret")); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// serve starts the server over the graph on a local port and returns a client connected to it
+func serve(t *testing.T, graph *testutil.Querier) searchpb.ProviderSearchClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	New(enrollment.NewSearcher(graph, 2)).Register(srv)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, lis.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return searchpb.NewProviderSearchClient(conn)
+}
+
+func TestServer_Search(t *testing.T) {
+	graph := &testutil.Querier{
+		Page:  `{"@type":"g:List","@value":["s1","s2","s3"]}`,
+		Count: `{"@type":"g:List","@value":[{"@type":"g:Int64","@value":5}]}`,
+	}
+	client := serve(t, graph)
+
+	req := &searchpb.SearchRequest{
+		PostalCode: "78704",
+		CareType:   "childCare",
+		HourlyRate: &searchpb.HourlyRate{Min: 10, Max: 30},
+		PageSize:   10,
+	}
+	res, err := client.Search(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1", "s2"}, res.SitterIds)
+	assert.NotEmpty(t, res.NextPageToken)
+	assert.Equal(t, int64(5), res.Total)
+
+	// the page query is the one BuildQuery builds for the same request with page size clamped by the server
+	expected, bindings, err := enrollment.BuildQuery(&enrollment.GRPCModel{
+		PostalCode: "78704",
+		CareType:   "childCare",
+		HourlyRate: &enrollment.HourlyRateGRPCModel{Min: 10, Max: 30},
+		PageSize:   2,
+	})
+	require.NoError(t, err)
+	require.Len(t, graph.Queries(), 2)
+	assert.Equal(t, expected.String(), graph.Queries()[0])
+	assert.Equal(t, map[string]string(bindings), graph.Bindings()[0])
+
+	// the next page continues after the last sitter
+	graph.Page = `{"@type":"g:List","@value":["s3"]}`
+	req.PageToken = res.NextPageToken
+	res, err = client.Search(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s3"}, res.SitterIds)
+	assert.Empty(t, res.NextPageToken)
+	assert.Equal(t, "s2", graph.Bindings()[2]["lastSitterID"])
+}
+
+func TestServer_Search_Profiles(t *testing.T) {
+	graph := &testutil.Querier{
+		Page: `{"@type":"g:List","@value":[{"@type":"g:Map","@value":[` +
+			`"sitter_id","s1","gender","female","avg_rank",{"@type":"g:Double","@value":4.5},` +
+			`"years_of_exp",{"@type":"g:Int32","@value":3},"service","petCare",` +
+			`"min_rate",{"@type":"g:Double","@value":10},"max_rate",{"@type":"g:Double","@value":20},"zip","78704"]}]}`,
+		Count: `{"@type":"g:List","@value":[{"@type":"g:Int64","@value":1}]}`,
+	}
+	client := serve(t, graph)
+
+	res, err := client.Search(context.Background(), &searchpb.SearchRequest{
+		CareType:   "petCare",
+		Projection: searchpb.Projection_PROJECTION_PROFILES,
+		Sort:       &searchpb.Sort{Field: searchpb.SortField_SORT_FIELD_AVG_RANK, Direction: searchpb.SortDirection_SORT_DIRECTION_DESC},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1"}, res.SitterIds)
+	require.Len(t, res.Providers, 1)
+	p := res.Providers[0]
+	assert.Equal(t, "s1", p.SitterId)
+	assert.Equal(t, "female", p.Gender)
+	assert.Equal(t, 4.5, p.AvgRank)
+	assert.Equal(t, int32(3), p.YearsOfExp)
+	assert.Equal(t, "petCare", p.Service)
+	assert.Equal(t, 20.0, p.MaxRate)
+	assert.Equal(t, "78704", p.Zip)
+	assert.Contains(t, graph.Queries()[0], "avg_rank")
+}
+
+func TestServer_Search_Errors(t *testing.T) {
+	ok := `{"@type":"g:List","@value":[]}`
+
+	client := serve(t, &testutil.Querier{Page: ok, Count: ok})
+	_, err := client.Search(context.Background(), &searchpb.SearchRequest{CareType: "unknown"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Search(context.Background(), &searchpb.SearchRequest{
+		CareType:     "childCare",
+		Availability: &searchpb.TimeWindow{From: timestamppb.Now(), TimeZone: "UTC"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	for _, zone := range []string{"", "Mars/Olympus"} {
+		_, err = client.Search(context.Background(), &searchpb.SearchRequest{
+			CareType:     "childCare",
+			Availability: &searchpb.TimeWindow{From: timestamppb.Now(), To: timestamppb.Now(), TimeZone: zone},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), zone)
+	}
+
+	_, err = client.Search(context.Background(), &searchpb.SearchRequest{CareType: "childCare", Sort: &searchpb.Sort{Field: 42}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	client = serve(t, &testutil.Querier{Err: errors.New("connection lost")})
+	_, err = client.Search(context.Background(), &searchpb.SearchRequest{CareType: "childCare"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "connection lost")
+
+	client = serve(t, &testutil.Querier{Page: `{"@type":"g:List","@value":[{"@type":"g:Vertex"}]}`, Count: ok})
+	_, err = client.Search(context.Background(), &searchpb.SearchRequest{CareType: "childCare"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func Test_toStatus(t *testing.T) {
+	build := &enrollment.BuildError{Err: enrollment.ErrPageTokenMismatch}
+	assert.Equal(t, codes.InvalidArgument, status.Code(toStatus(build)))
+	assert.Equal(t, codes.InvalidArgument, status.Code(toStatus(enrollment.Validate(nil, 0))))
+	assert.Equal(t, codes.Internal, status.Code(toStatus(enrollment.ErrNoPageTokenSecret)))
+	assert.Equal(t, codes.Internal, status.Code(toStatus(&enrollment.BuildError{Err: enrollment.ErrNoPageTokenSecret})))
+}
+
+func TestServer_Search_AvailabilityTimeZone(t *testing.T) {
+	ok := `{"@type":"g:List","@value":[]}`
+	graph := &testutil.Querier{Page: ok, Count: ok}
+	client := serve(t, graph)
+
+	// Saturday 15:00-17:00 UTC is Saturday 10:00-12:00 in Austin
+	from := time.Date(2020, 10, 10, 15, 0, 0, 0, time.UTC)
+	_, err := client.Search(context.Background(), &searchpb.SearchRequest{
+		CareType: "childCare",
+		Availability: &searchpb.TimeWindow{
+			From:     timestamppb.New(from),
+			To:       timestamppb.New(from.Add(2 * time.Hour)),
+			TimeZone: "America/Chicago",
+		},
+		PageSize: 2,
+	})
+	require.NoError(t, err)
+
+	austin, err := time.LoadLocation("America/Chicago")
+	require.NoError(t, err)
+	expected, bindings, err := enrollment.BuildQuery(&enrollment.GRPCModel{
+		CareType: "childCare",
+		Availability: &enrollment.TimeWindowGRPCModel{
+			From: time.Date(2020, 10, 10, 10, 0, 0, 0, austin),
+			To:   time.Date(2020, 10, 10, 12, 0, 0, 0, austin),
+		},
+		PageSize: 2,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, graph.Queries())
+	assert.Equal(t, expected.String(), graph.Queries()[0])
+	assert.Equal(t, map[string]string(bindings), graph.Bindings()[0])
+	assert.Equal(t, "600", graph.Bindings()[0]["startMinute0"])
+}
+
+func TestToModel_CareTypeMatch(t *testing.T) {
+	for match, expected := range map[searchpb.CareTypeMatch]enrollment.CareTypeMatch{
+		searchpb.CareTypeMatch_CARE_TYPE_MATCH_UNSPECIFIED: enrollment.MatchAnyCareType,
+		searchpb.CareTypeMatch_CARE_TYPE_MATCH_ANY:         enrollment.MatchAnyCareType,
+		searchpb.CareTypeMatch_CARE_TYPE_MATCH_ALL:         enrollment.MatchAllCareTypes,
+	} {
+		model, err := toModel(&searchpb.SearchRequest{CareType: "childCare", CareTypeMatch: match})
+		require.NoError(t, err)
+		assert.Equal(t, expected, model.CareTypeMatch, match.String())
+	}
+	_, err := toModel(&searchpb.SearchRequest{CareType: "childCare", CareTypeMatch: 42})
+	assert.Error(t, err)
+}